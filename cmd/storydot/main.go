@@ -0,0 +1,37 @@
+//Command storydot prints the story world as Graphviz DOT graph:
+//
+//	storydot -story story.json -hidden | dot -Tpng -o story.png
+//
+//The sample game is used if the story file is not set.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"storyteller/dot"
+	"storyteller/engine"
+	"storyteller/game"
+	"storyteller/storyloader"
+)
+
+func main() {
+	storyFile := flag.String("story", "", "Story file, sample game is used if empty")
+	hidden := flag.Bool("hidden", false, "Include hidden items")
+	flag.Parse()
+
+	var story engine.Adventurer = game.Sample()
+	if *storyFile != "" {
+		loaded, err := storyloader.LoadFile(*storyFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		story = loaded
+	}
+
+	if err := dot.Write(os.Stdout, story.BasicGame(), dot.Options{Hidden: *hidden}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
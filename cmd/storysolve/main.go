@@ -0,0 +1,63 @@
+//Command storysolve explores the story looking for the shortest ways to its endings:
+//
+//	storysolve -story story.json -depth 20
+//
+//The sample game is used if the story file is not set. Exit code is 1 if no winning ending was found.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"storyteller/engine"
+	"storyteller/game"
+	"storyteller/solver"
+	"storyteller/storyloader"
+	"strings"
+)
+
+func main() {
+	storyFile := flag.String("story", "", "Story file, sample game is used if empty")
+	depth := flag.Int("depth", 12, "Maximal number of commands")
+	states := flag.Int("states", 10000, "Maximal number of explored states")
+	seed := flag.Int64("seed", 1, "Seed of the game random source")
+	flag.Parse()
+
+	var story engine.Adventurer = game.Sample()
+	if *storyFile != "" {
+		loaded, err := storyloader.LoadFile(*storyFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		story = loaded
+	}
+
+	report, err := solver.Solve(story, solver.Options{MaxDepth: *depth, MaxStates: *states, Seed: *seed})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	complete := "complete"
+	if !report.IsComplete {
+		complete = "stopped at the limits"
+	}
+	fmt.Printf("Explored %d states, %s.\n", report.States, complete)
+
+	for _, solution := range report.Solutions {
+		kind := map[engine.EndingKind]string{engine.Win: "win", engine.Lose: "lose", engine.Neutral: "end"}[solution.Ending.Kind]
+		fmt.Printf("%s %s (%d): %s\n", kind, solution.Ending.ID, len(solution.Commands), strings.Join(solution.Commands, ", "))
+	}
+	if len(report.UnvisitedRooms) > 0 {
+		fmt.Println("Unvisited rooms:", strings.Join(report.UnvisitedRooms, ", "))
+	}
+	if len(report.UnseenItems) > 0 {
+		fmt.Println("Unseen items:", strings.Join(report.UnseenItems, ", "))
+	}
+
+	if !report.IsWinnable() {
+		fmt.Println("No winning ending found.")
+		os.Exit(1)
+	}
+}
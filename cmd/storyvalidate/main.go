@@ -0,0 +1,39 @@
+//Command storyvalidate checks the story world and prints found problems:
+//
+//	storyvalidate -story story.json
+//
+//The sample game is used if the story file is not set. Exit code is 1 if there are errors.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"storyteller/engine"
+	"storyteller/game"
+	"storyteller/storyloader"
+	"storyteller/validate"
+)
+
+func main() {
+	storyFile := flag.String("story", "", "Story file, sample game is used if empty")
+	flag.Parse()
+
+	var story engine.Adventurer = game.Sample()
+	if *storyFile != "" {
+		loaded, err := storyloader.LoadFile(*storyFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		story = loaded
+	}
+
+	problems := validate.Check(story.BasicGame())
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+	if validate.HasErrors(problems) {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,66 @@
+//Command storywalk plays walkthrough files and reports the first response which differs:
+//
+//	storywalk -story story.json story.walk
+//
+//With -record it prints the walkthrough with actual responses instead, use it to make snapshots:
+//
+//	storywalk -record commands.walk > story.walk
+//
+//The sample game is used if the story file is not set.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"storyteller/engine"
+	"storyteller/game"
+	"storyteller/storyloader"
+	"storyteller/walkthrough"
+)
+
+func main() {
+	storyFile := flag.String("story", "", "Story file, sample game is used if empty")
+	record := flag.Bool("record", false, "Print the walkthrough with actual responses")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: storywalk [-story story.json] [-record] file.walk...")
+		os.Exit(2)
+	}
+
+	newGame := func() engine.Adventurer {
+		if *storyFile == "" {
+			return game.Sample()
+		}
+		story, err := storyloader.LoadFile(*storyFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return story
+	}
+
+	failed := false
+	for _, fileName := range flag.Args() {
+		walk, err := walkthrough.ParseFile(fileName)
+		if err == nil {
+			if *record {
+				err = walk.Record(newGame, os.Stdout)
+			} else {
+				err = walk.Run(newGame)
+			}
+		}
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "FAIL %s: %v\n", fileName, err)
+			failed = true
+		} else if !*record {
+			fmt.Println("ok", fileName)
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
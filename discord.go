@@ -3,12 +3,14 @@ package main
 import (
 	"flag"
 	"fmt"
+	"image/png"
 	"os"
 	"os/signal"
 	"path"
 	"regexp"
 	"storyteller/engine"
 	"storyteller/game"
+	"storyteller/storyloader"
 	"strings"
 	"syscall"
 	"time"
@@ -17,25 +19,43 @@ import (
 )
 
 type gameData struct {
-	game      *game.SampleGame
+	game      engine.Adventurer
 	timestamp time.Time
 }
 
 var (
-	token string
-	games map[string]*gameData
+	token     string
+	storyFile string
+	games     map[string]*gameData
 )
 
-const imageFolder = "game/"
+const (
+	imageFolder = "game/"
+	saveFolder  = "saves/"
+)
 
 func init() {
 	flag.StringVar(&token, "t", "", "Bot Token")
+	flag.StringVar(&storyFile, "story", "", "Story file, sample game is used if empty")
 	flag.Parse()
 	games = make(map[string]*gameData)
 	time.AfterFunc(time.Minute*15, checkExpired)
 }
 
+//newGame - creates fresh instance of the story
+func newGame() (engine.Adventurer, error) {
+	if storyFile == "" {
+		return game.Sample(), nil
+	}
+	return storyloader.LoadFile(storyFile)
+}
+
 func mainDiscord() {
+	if _, err := newGame(); err != nil {
+		fmt.Println("error loading story,", err)
+		return
+	}
+
 	dg, err := discordgo.New("Bot " + token)
 	if err != nil {
 		fmt.Println("error creating Discord session,", err)
@@ -77,12 +97,19 @@ func messageCreate(session *discordgo.Session, message *discordgo.MessageCreate)
 	input := strings.ToLower(message.Content)
 
 	if input == "start" || input == "restart" {
+		story, err := newGame()
+		if err != nil {
+			fmt.Println("error loading story,", err)
+			return
+		}
+
 		context := &gameData{
-			game:      game.Sample(),
+			game:      story,
 			timestamp: time.Now()}
 
 		games[message.Author.ID] = context
 
+		context.game.BasicGame().Begin()
 		messages := parse(context.game.Intro())
 		/*
 
@@ -109,6 +136,25 @@ func messageCreate(session *discordgo.Session, message *discordgo.MessageCreate)
 		return
 	}
 
+	if input == "restore" {
+		restored, err := restoreUserGame(message.Author.ID)
+		if err != nil {
+			session.ChannelMessageSend(message.ChannelID, "No saved game found.\nType \"start\" to play.")
+			return
+		}
+
+		context := &gameData{
+			game:      restored,
+			timestamp: time.Now()}
+
+		games[message.Author.ID] = context
+
+		for _, msg := range parse("Restored.\n\n" + context.game.BasicGame().Look()) {
+			session.ChannelMessageSendComplex(message.ChannelID, msg)
+		}
+		return
+	}
+
 	context, ok := games[message.Author.ID]
 	if !ok {
 		session.ChannelMessageSend(message.ChannelID, "No active game found.\nType \"start\" to play.")
@@ -117,7 +163,31 @@ func messageCreate(session *discordgo.Session, message *discordgo.MessageCreate)
 
 	context.timestamp = time.Now()
 
-	messages := parse(engine.Process(context.game, message.Content))
+	if input == "save" {
+		if err := saveUserGame(message.Author.ID, context.game); err != nil {
+			fmt.Println("error saving game,", err)
+			session.ChannelMessageSend(message.ChannelID, "Can't save the game, sorry.")
+			return
+		}
+		session.ChannelMessageSend(message.ChannelID, "Saved.")
+		return
+	}
+
+	if input == "map" {
+		fileName, err := saveUserMap(message.Author.ID, context.game)
+		if err != nil {
+			fmt.Println("error drawing map,", err)
+			session.ChannelMessageSend(message.ChannelID, "```\n"+context.game.BasicGame().ShowMap()+"\n```")
+			return
+		}
+		for _, msg := range parse("[[img=" + fileName + "]]") {
+			session.ChannelMessageSendComplex(message.ChannelID, msg)
+		}
+		return
+	}
+
+	response := engine.Process(context.game, message.Content)
+	messages := parse(markEnding(context.game.BasicGame().Ending, response))
 
 	for _, msg := range messages {
 		session.ChannelMessageSendComplex(message.ChannelID, msg)
@@ -135,6 +205,73 @@ func checkExpired() {
 	time.AfterFunc(time.Minute*15, checkExpired)
 }
 
+func userSaveFile(userID string) string {
+	current, _ := os.Executable()
+	return path.Join(path.Dir(current), saveFolder, userID+".json")
+}
+
+func saveUserGame(userID string, context engine.Adventurer) error {
+	fileName := userSaveFile(userID)
+	if err := os.MkdirAll(path.Dir(fileName), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return context.BasicGame().Save(f)
+}
+
+//saveUserMap draws the map into the image folder, so it can be attached by parse
+func saveUserMap(userID string, context engine.Adventurer) (string, error) {
+	fileName := "map_" + userID + ".png"
+	current, _ := os.Executable()
+
+	f, err := os.Create(path.Join(path.Dir(current), imageFolder, fileName))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	return fileName, png.Encode(f, context.BasicGame().MapImage())
+}
+
+func restoreUserGame(userID string) (engine.Adventurer, error) {
+	f, err := os.Open(userSaveFile(userID))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	context, err := newGame()
+	if err != nil {
+		return nil, err
+	}
+	if err = context.BasicGame().Load(f); err != nil {
+		return nil, err
+	}
+	return context, nil
+}
+
+//markEnding makes the ending banner stand out
+func markEnding(ending *engine.Ending, msg string) string {
+	if ending == nil {
+		return msg
+	}
+
+	icon := "\U0001F3C1"
+	switch ending.Kind {
+	case engine.Win:
+		icon = "\U0001F3C6"
+	case engine.Lose:
+		icon = "\U0001F480"
+	}
+	return strings.Replace(msg, ending.Banner(), icon+" **"+ending.Banner()+"** "+icon, 1)
+}
+
 func parse(src string) []*discordgo.MessageSend {
 	result := []*discordgo.MessageSend{}
 	re := regexp.MustCompile(`\[\[([^\[\]]*)\]\]`)
@@ -0,0 +1,168 @@
+//Package dot exports the story world as Graphviz DOT graph: rooms, exits, items and keys
+package dot
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"storyteller/engine"
+	"strings"
+)
+
+//Options - what to include into the graph
+type Options struct {
+	Hidden bool //items with IsVisible=false
+}
+
+type writer struct {
+	out     io.Writer
+	options Options
+	ids     map[engine.Itemer]string //doors are placed in both rooms, so items can be met twice
+	names   map[string][]string      //node IDs of the items by name, names aren't unique
+	keys    [][2]string              //key name and node ID of the locked item
+	err     error
+}
+
+//Write - writes DOT graph of the game world
+func Write(out io.Writer, game *engine.Game, options Options) error {
+	w := &writer{
+		out:     out,
+		options: options,
+		ids:     make(map[engine.Itemer]string),
+		names:   make(map[string][]string)}
+
+	names := []string{}
+	for name := range game.Rooms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w.printf("digraph story {\n")
+	w.printf("  node [fontname=\"Helvetica\" fontsize=10];\n")
+	w.printf("  edge [fontname=\"Helvetica\" fontsize=9];\n\n")
+
+	for _, name := range names {
+		w.room(game, name)
+	}
+
+	if len(game.Inventory) > 0 {
+		w.printf("  %s [label=\"inventory\" shape=folder];\n", quote("inventory"))
+		w.contents("inventory", game.Inventory)
+	}
+
+	for _, name := range names {
+		room := game.Rooms[name].BasicRoom()
+		dirs := []string{}
+		for dir := range room.Exits {
+			dirs = append(dirs, dir)
+		}
+		sort.Strings(dirs)
+
+		for _, dir := range dirs {
+			w.printf("  %s -> %s [label=%s];\n", quote("room:"+name), quote("room:"+room.Exits[dir]), quote(dir))
+		}
+	}
+
+	for _, key := range w.keys {
+		if len(w.names[key[0]]) == 0 {
+			//key is hidden or appears only during the game
+			id := "key:" + key[0]
+			w.names[key[0]] = []string{id}
+			w.printf("  %s [label=%s shape=ellipse style=dotted];\n", quote(id), quote(key[0]))
+		}
+		for _, id := range w.names[key[0]] {
+			w.printf("  %s -> %s [style=dotted color=blue label=\"key\"];\n", quote(id), quote(key[1]))
+		}
+	}
+
+	w.printf("}\n")
+	return w.err
+}
+
+func (w *writer) room(game *engine.Game, name string) {
+	room := game.Rooms[name].BasicRoom()
+
+	attrs := []string{"shape=box"}
+	label := name
+	if name == game.Location {
+		attrs = append(attrs, "peripheries=2")
+	}
+	if room.Locked != "" {
+		label += "\n(locked)"
+		attrs = append(attrs, "color=red")
+	}
+	if room.IsDark {
+		attrs = append(attrs, "style=filled", "fillcolor=gray80")
+	}
+	w.printf("  %s [label=%s %s];\n", quote("room:"+name), quote(label), strings.Join(attrs, " "))
+
+	w.contents("room:"+name, room.Items)
+}
+
+//contents - items with containment edges from the owner
+func (w *writer) contents(owner string, items []engine.Itemer) {
+	for _, i := range items {
+		item := i.Basic()
+		if !item.IsVisible && !w.options.Hidden {
+			continue
+		}
+
+		id, ok := w.ids[i]
+		if !ok {
+			id = fmt.Sprintf("item:%d", len(w.ids)+1)
+			w.ids[i] = id
+			w.item(i, id)
+			w.contents(id, item.Items)
+		}
+		w.printf("  %s -> %s [style=dashed arrowhead=none];\n", quote(owner), quote(id))
+	}
+}
+
+func (w *writer) item(i engine.Itemer, id string) {
+	item := i.Basic()
+
+	attrs := []string{"shape=ellipse"}
+	notes := []string{}
+	if _, ok := i.(engine.Actor); ok {
+		attrs = []string{"shape=egg"}
+	}
+	if _, ok := i.(engine.Doorer); ok {
+		attrs = []string{"shape=diamond"}
+	}
+	if item.IsLocked {
+		notes = append(notes, "locked")
+		attrs = append(attrs, "color=red")
+	}
+	if item.IsLightSource {
+		notes = append(notes, "light")
+	}
+	if !item.IsVisible {
+		notes = append(notes, "hidden")
+		attrs = append(attrs, "style=dashed", "fontcolor=gray40")
+	}
+
+	label := item.Name
+	if len(notes) > 0 {
+		label += "\n(" + strings.Join(notes, ", ") + ")"
+	}
+	w.printf("  %s [label=%s %s];\n", quote(id), quote(label), strings.Join(attrs, " "))
+	w.names[item.Name] = append(w.names[item.Name], id)
+
+	if item.KeyName != "" {
+		w.keys = append(w.keys, [2]string{item.KeyName, id})
+	}
+}
+
+func (w *writer) printf(format string, args ...interface{}) {
+	if w.err == nil {
+		_, w.err = fmt.Fprintf(w.out, format, args...)
+	}
+}
+
+//quote - DOT string, new lines are kept as \n
+func quote(text string) string {
+	text = strings.Replace(text, "\\", "\\\\", -1)
+	text = strings.Replace(text, "\"", "\\\"", -1)
+	text = strings.Replace(text, "\n", "\\n", -1)
+	return "\"" + text + "\""
+}
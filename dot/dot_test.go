@@ -0,0 +1,22 @@
+package dot
+
+import (
+	"bytes"
+	"storyteller/engine"
+	"strings"
+	"testing"
+)
+
+func TestItemsWithTheSameName(t *testing.T) {
+	game := &engine.Game{Location: "Hall", Rooms: make(map[string]engine.Spacer)}
+	game.Rooms["Hall"] = &engine.Room{Desc: "Hall.", Items: []engine.Itemer{&engine.Item{Name: "coin", IsVisible: true}}}
+	game.Rooms["Study"] = &engine.Room{Desc: "Study.", Items: []engine.Itemer{&engine.Item{Name: "coin", IsVisible: true}}}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, game, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if count := strings.Count(buf.String(), `[label="coin"`); count != 2 {
+		t.Fatalf("%d coin nodes instead of 2:\n%s", count, buf.String())
+	}
+}
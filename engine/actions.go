@@ -48,9 +48,16 @@ var TAKE = &Action{
 	IsItemRequired: true,
 	IsPredefined:   true}
 
-//PUT action
+//PUT action, "in" and "on" before the target are skipped
 var PUT = &Action{
-	Name:           "put",
+	Name:             "put",
+	IsItemRequired:   true,
+	IsTargetRequired: true,
+	IsPredefined:     true}
+
+//DROP action
+var DROP = &Action{
+	Name:           "drop",
 	IsItemRequired: true,
 	IsPredefined:   true}
 
@@ -77,6 +84,14 @@ var UNLOCK = &Action{
 	IsTargetRequired: true,
 	IsPredefined:     true}
 
+//LOCK action
+var LOCK = &Action{
+	Name:             "lock",
+	Syntax:           "with",
+	IsItemRequired:   true,
+	IsTargetRequired: true,
+	IsPredefined:     true}
+
 //GIVE action
 var GIVE = &Action{
 	Name:               "give",
@@ -86,3 +101,15 @@ var GIVE = &Action{
 	IsTopicRequired:    true,
 	DefaultTopicAnswer: "\"I don't need it.\"",
 	IsPredefined:       true}
+
+//LIGHT action
+var LIGHT = &Action{
+	Name:           "light",
+	IsItemRequired: true,
+	IsPredefined:   true}
+
+//EXTINGUISH action
+var EXTINGUISH = &Action{
+	Name:           "extinguish",
+	IsItemRequired: true,
+	IsPredefined:   true}
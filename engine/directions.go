@@ -0,0 +1,65 @@
+package engine
+
+type direction struct {
+	name     string
+	short    string
+	opposite string
+	dx, dy   int //offset on the map, zero for directions which can't be drawn
+}
+
+//compass and vertical directions, in the order they are listed
+var directions = []direction{
+	{"north", "n", "south", 0, -1},
+	{"northeast", "ne", "southwest", 1, -1},
+	{"east", "e", "west", 1, 0},
+	{"southeast", "se", "northwest", 1, 1},
+	{"south", "s", "north", 0, 1},
+	{"southwest", "sw", "northeast", -1, 1},
+	{"west", "w", "east", -1, 0},
+	{"northwest", "nw", "southeast", -1, -1},
+	{"up", "u", "down", 0, 0},
+	{"down", "d", "up", 0, 0},
+	{"in", "", "out", 0, 0},
+	{"out", "", "in", 0, 0}}
+
+//Direction - full direction name by the word like "n" or "north", empty for unknown direction
+func Direction(word string) string {
+	for _, dir := range directions {
+		if word == dir.name || (word == dir.short && word != "") {
+			return dir.name
+		}
+	}
+	return ""
+}
+
+//Opposite - reverse direction, empty for named exits like "ladder"
+func Opposite(dir string) string {
+	for _, check := range directions {
+		if dir == check.name {
+			return check.opposite
+		}
+	}
+	return ""
+}
+
+//Connect - adds exit between rooms, the way back is added too, if the direction has the opposite one
+//and the room doesn't have that exit yet
+func (game *Game) Connect(from string, dir string, to string) {
+	room := game.Rooms[from].BasicRoom()
+	if room.Exits == nil {
+		room.Exits = make(map[string]string)
+	}
+	room.Exits[dir] = to
+
+	back := Opposite(dir)
+	if back == "" {
+		return
+	}
+	target := game.Rooms[to].BasicRoom()
+	if target.Exits == nil {
+		target.Exits = make(map[string]string)
+	}
+	if _, ok := target.Exits[back]; !ok {
+		target.Exits[back] = from
+	}
+}
@@ -0,0 +1,168 @@
+package engine
+
+//Door - item between two rooms, it blocks the way while closed.
+//The same door instance is placed in both rooms, see AddDoor.
+type Door struct {
+	Item
+	Connects [2]string //names of the rooms
+}
+
+//Doorer - door interface
+type Doorer interface {
+	Itemer
+	BasicDoor() *Door
+}
+
+func init() {
+	RegisterItem("door", func(game *Game) Itemer { return &Door{} })
+}
+
+//BasicDoor - provides general door data
+func (door *Door) BasicDoor() *Door {
+	return door
+}
+
+//AddDoor - places the door in both rooms it connects
+func (game *Game) AddDoor(door Doorer) {
+	base := door.BasicDoor()
+	base.Location = base.Connects[0]
+	for _, name := range base.Connects {
+		if room := game.Rooms[name]; room != nil {
+			room.BasicRoom().Items = append(room.BasicRoom().Items, door)
+		}
+	}
+}
+
+//OnAction - doors can be opened, closed, locked and unlocked, but they stay in place
+func (door *Door) OnAction(action *Action, target Itemer) (string, string) {
+	switch action {
+	case OPEN:
+		return door.Open(), door.Location
+	case CLOSE:
+		return door.Close(), door.Location
+	case UNLOCK:
+		return door.Unlock(target), door.Location
+	case LOCK:
+		return door.Lock(target), door.Location
+	}
+	return door.Item.OnAction(action, target)
+}
+
+//Open the door
+func (door *Door) Open() string {
+	if door.IsOpen {
+		return "It's already opened."
+	}
+	if door.IsLocked {
+		return "It's locked."
+	}
+
+	door.IsOpen = true
+	if msg, ok := door.DefaultActionDesc["open"]; ok {
+		return msg
+	}
+	return "Opened."
+}
+
+//Close the door
+func (door *Door) Close() string {
+	if !door.IsOpen {
+		return "It's already closed."
+	}
+
+	door.IsOpen = false
+	if msg, ok := door.DefaultActionDesc["close"]; ok {
+		return msg
+	}
+	return "Closed."
+}
+
+//Unlock the door with its key
+func (door *Door) Unlock(target Itemer) string {
+	if !door.IsLocked {
+		return "It's not locked."
+	}
+	if msg := door.checkKey(target); msg != "" {
+		return msg
+	}
+
+	door.IsLocked = false
+	return "Unlocked."
+}
+
+//Lock the door with its key
+func (door *Door) Lock(target Itemer) string {
+	if door.IsLocked {
+		return "It's already locked."
+	}
+	if door.IsOpen {
+		return "You need to close it first."
+	}
+	if msg := door.checkKey(target); msg != "" {
+		return msg
+	}
+
+	door.IsLocked = true
+	return "Locked."
+}
+
+func (door *Door) checkKey(target Itemer) string {
+	if target == nil || door.KeyName == "" {
+		return "You need a key."
+	}
+
+	key := target.Basic()
+	if key.Location != "inventory" {
+		return "You are not holding " + key.NameWithArticle() + "."
+	}
+	if key.Name != door.KeyName {
+		return "It doesn't fit."
+	}
+	return ""
+}
+
+//findDoor - door between the rooms, nil if there is no door
+func (game *Game) findDoor(from string, to string) *Door {
+	room := game.Rooms[from]
+	if room == nil {
+		return nil
+	}
+
+	for _, item := range room.BasicRoom().Items {
+		door, ok := item.(Doorer)
+		if !ok {
+			continue
+		}
+		base := door.BasicDoor()
+		if (base.Connects[0] == from && base.Connects[1] == to) ||
+			(base.Connects[1] == from && base.Connects[0] == to) {
+			return base
+		}
+	}
+	return nil
+}
+
+//linkDoors - saved games have a copy of the door in each room, it should be the same instance again.
+//Doors are told apart by the rooms they connect, so doors with the same name stay separate.
+func (game *Game) linkDoors() {
+	doors := make(map[[3]string]Itemer)
+	for _, room := range game.Rooms {
+		items := room.BasicRoom().Items
+		for idx, item := range items {
+			door, ok := item.(Doorer)
+			if !ok {
+				continue
+			}
+			rooms := door.BasicDoor().Connects
+			if rooms[0] > rooms[1] {
+				rooms[0], rooms[1] = rooms[1], rooms[0]
+			}
+			key := [3]string{item.Basic().Name, rooms[0], rooms[1]}
+			if linked, ok := doors[key]; ok {
+				items[idx] = linked
+			} else {
+				doors[key] = item
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package engine
+
+import (
+	"bytes"
+	"testing"
+)
+
+//newDoorGame - Hall, Study to the north and Kitchen to the east of it, both ways have a door named "door"
+func newDoorGame() *Game {
+	game := &Game{Location: "Hall", Rooms: make(map[string]Spacer)}
+	for _, name := range []string{"Hall", "Study", "Kitchen"} {
+		game.Rooms[name] = &Room{Desc: name + "."}
+	}
+	game.Connect("Hall", "north", "Study")
+	game.Connect("Study", "east", "Kitchen")
+	game.AddDoor(&Door{Item: Item{Name: "door", IsVisible: true}, Connects: [2]string{"Hall", "Study"}})
+	game.AddDoor(&Door{Item: Item{Name: "door", IsVisible: true}, Connects: [2]string{"Study", "Kitchen"}})
+	return game
+}
+
+func TestDoorsWithTheSameNameAfterLoad(t *testing.T) {
+	game := newDoorGame()
+	game.Intro()
+	if msg := Process(game, "open door"); msg != "Opened." {
+		t.Fatalf("open door: %q", msg)
+	}
+
+	var buf bytes.Buffer
+	if err := game.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded := newDoorGame()
+	if err := loaded.Load(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	Process(loaded, "n")
+	if loaded.Location != "Study" {
+		t.Fatalf("the opened door should let the player in, location %q", loaded.Location)
+	}
+	if msg := Process(loaded, "e"); loaded.Location != "Study" {
+		t.Fatalf("the closed door to the Kitchen was passed: %q", msg)
+	}
+}
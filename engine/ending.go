@@ -0,0 +1,134 @@
+package engine
+
+import (
+	"bytes"
+	"strings"
+)
+
+//EndingKind - how the game ended for the player
+type EndingKind int
+
+//Ending kinds
+const (
+	Neutral EndingKind = iota
+	Win
+	Lose
+)
+
+//Ending - named game ending, like "death by arrows" or "the witch is happy"
+type Ending struct {
+	ID   string
+	Kind EndingKind
+	Text string //epilogue shown after the final turn
+}
+
+//Banner - ending headline, frontends can find it in the output to render it differently
+func (ending *Ending) Banner() string {
+	switch ending.Kind {
+	case Win:
+		return "*** You have won ***"
+	case Lose:
+		return "*** You have lost ***"
+	}
+	return "*** The End ***"
+}
+
+//Finish - ends the game with the ending from Game.Endings, returns false if ending is unknown
+func (game *Game) Finish(id string) bool {
+	ending := game.findEnding(id)
+	if ending == nil {
+		return false
+	}
+	game.Ending = ending
+	return true
+}
+
+//IsFinished - checks if the game has reached an ending
+func (game *Game) IsFinished() bool {
+	return game.Ending != nil
+}
+
+//Restart - returns the game to the state before the first turn, returns false if it wasn't started yet
+func (game *Game) Restart() bool {
+	if game.start == nil {
+		return false
+	}
+
+	//the random sequence goes on, so the new game isn't a copy of the previous one
+	seed, draws := game.Seed(), game.source.draws
+	if err := game.Load(bytes.NewReader(game.start)); err != nil {
+		return false
+	}
+	game.restoreRandom(seed, draws)
+	game.history = nil
+	return true
+}
+
+func (game *Game) findEnding(id string) *Ending {
+	for i := range game.Endings {
+		if game.Endings[i].ID == id {
+			return &game.Endings[i]
+		}
+	}
+	return nil
+}
+
+//Begin keeps the initial state for restart, frontends should call it before Intro.
+//Intro and the first command call it too, if it wasn't called yet.
+func (game *Game) Begin() {
+	if game.start != nil {
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := game.Save(&buf); err == nil {
+		game.start = buf.Bytes()
+	}
+}
+
+//showEnding - banner, epilogue, final score and post-game options
+func (game *Game) showEnding() string {
+	msg := "\n\n" + game.Ending.Banner()
+	if game.Ending.Text != "" {
+		msg += "\n\n" + game.Ending.Text
+	}
+	if len(game.Awards) > 0 {
+		msg += "\n\n" + game.ShowScore()
+	}
+	return msg + "\n\n" + game.postGameOptions()
+}
+
+func (game *Game) postGameOptions() string {
+	options := []string{"RESTART", "UNDO the last move"}
+	if len(game.Awards) > 0 {
+		options = append(options, "give the FULL SCORE for that game")
+	}
+	if game.Amusing != "" {
+		options = append(options, "see some suggestions for AMUSING things to do")
+	}
+
+	last := len(options) - 1
+	if last == 1 {
+		return "Would you like to " + options[0] + " or " + options[1] + "?"
+	}
+	return "Would you like to " + strings.Join(options[:last], ", ") + ", or " + options[last] + "?"
+}
+
+//postGame - commands available after the ending
+func postGame(game Adventurer, command string) string {
+	base := game.BasicGame()
+
+	switch command {
+	case "amusing":
+		if base.Amusing == "" {
+			return base.postGameOptions()
+		}
+		return base.Amusing
+	case "score":
+		return base.ShowScore()
+	case "full score", "fullscore", "full":
+		return base.ShowFullScore()
+	}
+
+	return base.postGameOptions()
+}
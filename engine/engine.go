@@ -1,19 +1,55 @@
 package engine
 
 import (
+	"bytes"
+	"math/rand"
 	"strings"
 )
 
 //Game - basic game structure
 type Game struct {
-	Location   string
-	Inventory  []Itemer
-	Rooms      map[string]Spacer
-	Actions    []Action
-	IsFinished bool
-	Input      string
+	Location  string
+	Inventory []Itemer
+	Rooms     map[string]Spacer
+	Actions   []Action
+	Verbs     []Verb //story grammar, tried before the actions and the built-in verbs
+	Rules     []Rule
+	Awards    []Award
+	Endings   []Ending
+	Ending    *Ending //reached ending, nil while the game goes on
+	Amusing   string  //suggestions shown after the ending
+	Input     string
+	UndoDepth int  //turns kept for undo, 0 - default depth, negative - disabled
+	ShowExits bool //list exits after room descriptions
+	Turns     int
+
+	prototypes map[string]interface{}
+	history    [][]byte
+	start      []byte
+	timers     []*Timer
+	handlers   map[string]func(game *Game) string
+
+	awarded       []string
+	notifications []string
+	failed        bool              //the last command wasn't understood or couldn't be done, see fail
+	question      *question         //the parser waits for clarification, see ask
+	pronouns      map[string]string //names of the last mentioned items by pronoun
+	them          []string          //names of the objects of the last command with several objects
+
+	seed   int64
+	source *countingSource
+	random *rand.Rand
 }
 
+//out of world commands, they don't take a turn
+var metaCommands = map[string]bool{
+	"help":       true,
+	"exits":      true,
+	"map":        true,
+	"score":      true,
+	"full score": true,
+	"fullscore":  true}
+
 //Adventurer interface for the game context
 type Adventurer interface {
 	BasicGame() *Game
@@ -33,8 +69,8 @@ func (game *Game) BasicGame() *Game {
 
 //Intro for the game
 func (game *Game) Intro() string {
-	room := game.Rooms[game.Location]
-	return "\n\nWELCOME!\n\n" + room.EnterRoom(game.Location) + "\n"
+	game.Begin()
+	return "\n\nWELCOME!\n\n" + game.EnterRoom() + "\n"
 }
 
 //CurrentRoom - returns current location
@@ -43,169 +79,217 @@ func (game *Game) CurrentRoom() Spacer {
 	return room
 }
 
-//Process command on game context
-func Process(game Adventurer, command string) string {
+//Process command on game context. The input may have several commands separated by periods,
+//"then", or commas and "and" followed by a verb, they are executed in order until one fails or the game ends.
+func Process(game Adventurer, input string) string {
+	base := game.BasicGame()
+	commands := splitCommands(base, strings.ToLower(input))
+	if len(commands) == 0 {
+		return processCommand(game, "")
+	}
+
+	responses := []string{}
+	for _, command := range commands {
+		responses = append(responses, processCommand(game, command))
+		if base.failed || base.IsFinished() {
+			break
+		}
+	}
+	return strings.Join(responses, "\n")
+}
+
+//processCommand - single command
+func processCommand(game Adventurer, command string) string {
 	command = strings.Replace(strings.TrimSpace(strings.ToLower(command)), "  ", " ", -1)
 
+	base := game.BasicGame()
+	base.failed = false
+
 	if command == "" {
-		return "I beg your pardon?"
+		return base.fail("I beg your pardon?")
+	}
+
+	if question := base.question; question != nil {
+		base.question = nil
+		if completed, ok := question.answer(base, command); ok {
+			command = completed
+		}
+	}
+
+	base.Begin()
+
+	if command == "restart" {
+		if !base.Restart() {
+			return "You can't restart the game."
+		}
+		return game.Intro()
+	}
+
+	if command == "undo" {
+		if !base.Undo() {
+			return "You can't undo any further."
+		}
+		return "Previous turn undone.\n\n" + base.Look()
+	}
+
+	if base.IsFinished() {
+		return postGame(game, command)
 	}
 
-	if game.BasicGame().IsFinished {
-		return "Game is finished, but you can restart it."
+	if metaCommands[command] {
+		return executeCommand(game, command)
 	}
 
+	snapshot := base.snapshot()
 	msg := executeCommand(game, command)
+	if base.failed && (snapshot == nil || bytes.Equal(snapshot, base.snapshot())) {
+		//not understood or nothing happened, the command doesn't take a turn
+		return msg
+	}
+	msg += base.tick()
+	msg += base.notify()
+	if base.IsFinished() {
+		msg += base.showEnding()
+	}
+	base.remember(snapshot)
 	return msg
 }
 
 func executeCommand(game Adventurer, command string) string {
-	game.BasicGame().Input = command
+	base := game.BasicGame()
+	base.Input = command
+	exits := game.CurrentRoom().BasicRoom().Exits
 
-	room := game.CurrentRoom()
-	base := room.BasicRoom()
-	words := strings.Split(command, " ")
-
-	for i, word := range words {
-		switch word {
-		case "go", "the", "a", "an", "from":
-			//skip
-		case "north", "n":
-			return game.Navigate(base.North, "n")
-		case "south", "s":
-			return game.Navigate(base.South, "s")
-		case "east", "e":
-			return game.Navigate(base.East, "e")
-		case "west", "w":
-			return game.Navigate(base.West, "w")
-		case "look", "l":
-			return room.Look() + room.OnAction(LOOK)
-		case "examine", "x", "search":
-			return game.DoItemAction(words[i+1:], EXAMINE)
-		case "open":
-			return game.DoItemAction(words[i+1:], OPEN)
-		case "close":
-			return game.DoItemAction(words[i+1:], CLOSE)
-		case "unlock":
-			return game.DoItemAction(words[i+1:], UNLOCK)
-		case "take", "pick":
-			return game.DoItemAction(words[i+1:], TAKE)
-		case "put", "drop":
-			return game.DoItemAction(words[i+1:], PUT)
-		case "use":
-			return game.DoItemAction(words[i+1:], USE)
-		case "ask", "tell", "talk":
-			return game.DoActorAction(words[i+1:], ASK) + room.OnAction(ASK)
-
-		case "give", "show":
-			return game.DoItemAction(words[i+1:], GIVE)
-
-		case "help":
-			return game.Help()
-
-		case "inventory", "i":
-			return game.ShowInventory() + room.OnAction(INVENTORY)
-		default:
-			//check custom actions
-			for _, action := range game.BasicGame().Actions {
-				if word == action.Name {
-					if action.IsItemRequired {
-						return game.DoItemAction(words[i+1:], &action)
-					}
-					if action.IsActorRequired {
-						return game.DoActorAction(words[i+1:], &action)
-					}
-					return room.OnAction(&action)
-				}
-			}
-			return "I don't know the word \"" + word + "\"."
+	words := strings.Fields(command)
+	for len(words) > 0 && articles[words[0]] {
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return ""
+	}
+
+	dir := Direction(words[0])
+	if dir != "" && len(words) == 1 {
+		return game.Navigate(exits[dir], dir)
+	}
+	//check named exits, like "ladder"
+	if name := strings.Join(words, " "); exits[name] != "" {
+		return game.Navigate(exits[name], name)
+	}
+	if dir != "" {
+		return base.fail("I only understood you as far as wanting to go " + dir + ".")
+	}
+
+	for _, verb := range base.verbs() {
+		if matched, ok := verb.match(words); ok {
+			return verb.run(game, matched)
 		}
 	}
 
-	return ""
+	if base.isVerb(words[0]) {
+		return base.fail("I didn't understand that sentence.")
+	}
+	return base.fail("I don't know the word \"" + words[0] + "\".")
 }
 
 //DoActorAction - generic actor action processor
 func (game *Game) DoActorAction(words []string, action *Action) string {
 	if len(words) == 0 {
-		return strings.Title(action.Name) + " who?"
+		return game.askFor(strings.Title(action.Name)+" who?", game.Input)
 	}
 
-	room := game.Rooms[game.Location]
-	items := visibleItems(room.BasicRoom().Items, false, true)
+	items := game.scope()
 	actors := filterActors(items)
 
 	if len(actors) == 0 {
-		return "There is nobody to " + action.Name + "."
+		return game.fail("There is nobody to " + action.Name + ".")
 	}
 
 	actor := actors[0]
 	if len(actors) > 1 {
 		var msg string
-		msg, actor, words = findActor(words, actors)
+		msg, actor, words = game.findActor(words, actors)
 		if msg != "" {
-			return msg
+			return game.fail(msg)
 		}
 	}
+	game.refer(actor)
 
 	if action.IsTopicRequired {
 		for _, topic := range findTopics(words, actor) {
 			if strings.Contains(topic.Action, action.Name) {
-				return actor.OnTopic(topic, action, nil)
+				return game.withRules(&ruleContext{action.Name, nameOf(actor), "", topic.Vocab}, func() string {
+					return game.talk(actor, topic, action, nil)
+				})
 			}
 		}
-		return actor.OnTopic(nil, action, nil)
+		return game.withRules(&ruleContext{action.Name, nameOf(actor), "", ""}, func() string {
+			return game.talk(actor, nil, action, nil)
+		})
 	}
 
+	isSyntaxFound := false
 	if action.Syntax != "" {
-		words, _ = findSyntax(words, action.Syntax)
+		words, isSyntaxFound = findSyntax(words, action.Syntax)
 	}
 
 	if action.IsTargetRequired && len(words) == 0 && action.Syntax != "" {
-		return strings.Title(action.Name) + " " + action.Syntax + " what?"
+		return game.askForTarget(action, isSyntaxFound)
 	}
 
-	msg, target, _ := findTarget(words, items, !action.IsTargetRequired)
+	msg, target, _ := game.findTarget(words, items, !action.IsTargetRequired)
 
-	if !action.IsTargetRequired {
-		msg, _ := actor.OnAction(action, target)
-		return msg
-	}
-
-	if msg != "" {
-		return msg
-	}
+	if action.IsTargetRequired {
+		if msg != "" {
+			return game.fail(msg)
+		}
 
-	if target == nil {
-		return strings.Title(action.Name) + " " + action.Syntax + " what?"
+		if target == nil {
+			return game.askForTarget(action, isSyntaxFound)
+		}
 	}
 
-	msg, _ = actor.OnAction(action, target)
-	return msg
+	return game.withRules(&ruleContext{action.Name, nameOf(actor), nameOf(target), ""}, func() string {
+		msg, _ := actor.OnAction(action, target)
+		return msg
+	})
 }
 
 //DoItemAction - generic item action processor
 func (game *Game) DoItemAction(words []string, action *Action) string {
 	if len(words) == 0 {
-		return strings.Title(action.Name) + " what?"
+		return game.askFor(strings.Title(action.Name)+" what?", game.Input)
 	}
 
-	room := game.Rooms[game.Location]
-	items := visibleItems(append(game.Inventory, room.BasicRoom().Items...), false, true)
+	items := game.scope()
+
+	if objects, words, msg, ok := game.findObjects(words, items, action); ok {
+		if msg != "" {
+			return game.fail(msg)
+		}
+		game.referAll(objects)
+		return game.doEach(objects, words, items, action)
+	}
 
-	msg, item, words := findTarget(words, items, false)
+	msg, item, words := game.findTarget(words, items, false)
 
 	if msg != "" {
-		return msg
+		return game.fail(msg)
 	}
+	game.refer(item)
+
+	return game.doItemAction(item, words, items, action)
+}
 
+//doItemAction - the rest of the command after the direct object
+func (game *Game) doItemAction(item Itemer, words []string, items []Itemer, action *Action) string {
 	isSyntaxFound := false
 	if action.Syntax != "" {
 		words, isSyntaxFound = findSyntax(words, action.Syntax)
 	}
 
-	if action.IsTargetRequired && len(words) == 0 && action.Syntax != "" {
-		return strings.Title(action.Name) + " " + action.Syntax + " what?"
+	if action.IsTargetRequired && len(words) == 0 {
+		return game.askForTarget(action, isSyntaxFound)
 	}
 
 	if action.IsActorTarget {
@@ -216,58 +300,72 @@ func (game *Game) DoItemAction(words []string, action *Action) string {
 		if len(actors) == 1 && !isSyntaxFound && action.Syntax != "" {
 			actor = actors[0]
 		} else {
-			msg, actor, _ = findActor(words, actors)
+			msg, actor, _ = game.findActor(words, actors)
 			if msg != "" {
-				return msg
+				return game.fail(msg)
 			}
 		}
+		game.refer(actor)
 
 		if action.IsTopicRequired {
 			for _, topic := range findTopics(strings.Split(item.Basic().Name, " "), actor) {
 				if strings.Contains(topic.Action, action.Name) {
-					if topic.IsItemConsumed {
-						game.ChangeParent(item, "")
-					}
-					msg = actor.OnTopic(topic, action, item)
-					return msg
+					return game.withRules(&ruleContext{action.Name, nameOf(item), nameOf(actor), topic.Vocab}, func() string {
+						if topic.IsItemConsumed {
+							game.ChangeParent(item, "")
+						}
+						return game.talk(actor, topic, action, item)
+					})
 				}
 			}
-			return actor.OnTopic(nil, action, item)
+			return game.withRules(&ruleContext{action.Name, nameOf(item), nameOf(actor), ""}, func() string {
+				return game.talk(actor, nil, action, item)
+			})
 		}
 
 		return game.finalizeItemAction(item, actor, action)
 	}
 
-	msg, target, _ := findTarget(words, items, !action.IsTargetRequired)
+	msg, target, _ := game.findTarget(words, items, !action.IsTargetRequired)
 
 	if !action.IsTargetRequired {
 		return game.finalizeItemAction(item, target, action)
 	}
 
 	if msg != "" {
-		return msg
+		return game.fail(msg)
 	}
 
 	if target == nil {
-		return strings.Title(action.Name) + " " + action.Syntax + " what?"
+		return game.askForTarget(action, isSyntaxFound)
 	}
 
 	return game.finalizeItemAction(item, target, action)
 }
 
 func (game *Game) finalizeItemAction(item Itemer, target Itemer, action *Action) string {
-	msg, parent := item.OnAction(action, target)
+	wasLit := game.IsLit()
+	msg := game.withRules(&ruleContext{action.Name, nameOf(item), nameOf(target), ""}, func() string {
+		msg, parent := item.OnAction(action, target)
 
-	if parent != item.Basic().Location {
-		game.ChangeParent(item, parent)
-	}
+		if parent != item.Basic().Location {
+			game.ChangeParent(item, parent)
+		}
+		return msg
+	})
 
-	return msg + game.Rooms[game.Location].OnAction(action)
+	return msg + game.lightChanged(wasLit) + game.Rooms[game.Location].OnAction(action)
 }
 
+//Here - parent name for the current room
+const Here = "here"
+
 //ChangeParent - move item to the new owner
 func (game *Game) ChangeParent(item Itemer, parentName string) {
 	room := game.CurrentRoom().BasicRoom()
+	if parentName == Here {
+		parentName = game.Location
+	}
 
 	//remove from previous owner
 	if item.Basic().Location == "inventory" {
@@ -306,11 +404,26 @@ func (game *Game) ChangeParent(item Itemer, parentName string) {
 	item.Basic().Location = parentName
 }
 
-//Navigate -
+//Navigate - moves the player, the command fails if the player stays in the room
 func (game *Game) Navigate(location string, dir string) string {
+	from := game.Location
+	msg := game.withRules(&ruleContext{"go", dir, location, ""}, func() string {
+		return game.navigate(location, dir)
+	})
+	if game.Location == from {
+		game.failed = true
+	}
+	return msg
+}
+
+func (game *Game) navigate(location string, dir string) string {
 	room := game.CurrentRoom()
 
 	if location != "" {
+		if door := game.findDoor(game.Location, location); door != nil && !door.IsOpen {
+			return "The " + door.Name + " is closed."
+		}
+
 		canLeave, msg := room.LeaveRoom(dir)
 		if !canLeave {
 			if msg == "" {
@@ -324,7 +437,14 @@ func (game *Game) Navigate(location string, dir string) string {
 		}
 		game.Location = location
 
-		return msg + room.EnterRoom(location)
+		if !game.IsLit() {
+			return msg + room.BasicRoom().darkness()
+		}
+		return msg + game.EnterRoom()
+	}
+
+	if msg, ok := room.BasicRoom().Blocked[dir]; ok {
+		return msg
 	}
 	return "You can't go that way."
 }
@@ -353,7 +473,8 @@ func (game *Game) ShowInventory() string {
 
 //Help - displays keywords
 func (game *Game) Help() string {
-	return `Navigation: (n)orth, (s)outh, (e)ast, (w)est.
-Useful verbs: (l)ook, e(x)amine, take, open, close, put _ on _, unlock _ with _
-Characters: ask _ about _, give _ to _`
+	return `Navigation: (n)orth, (s)outh, (e)ast, (w)est, ne, nw, se, sw, (u)p, (d)own, in, out, exits, map, go to _.
+Useful verbs: (l)ook, e(x)amine, take, open, close, put _ on _, (un)lock _ with _, light, extinguish
+Characters: ask _ about _, talk to _, give _ to _
+Game: score, full score, undo, save, restore, restart`
 }
@@ -0,0 +1,115 @@
+package engine
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+)
+
+//LeaveChecker - room which tells without side effects if the player can leave it.
+//Exits of other rooms are checked with LeaveRoom, its changes of the world are undone.
+type LeaveChecker interface {
+	CanLeave(dir string) bool
+}
+
+//EnterRoom - enters the current room, the description is followed by exits if Game.ShowExits is set
+func (game *Game) EnterRoom() string {
+	room := game.CurrentRoom()
+	isVisited := room.BasicRoom().IsVisited
+
+	msg := room.EnterRoom(game.Location)
+	if game.ShowExits && !isVisited {
+		msg += "\n" + game.DescribeExits()
+	}
+	return msg
+}
+
+//DescribeExits - list of exits like "Exits: north (Cave), south", rooms are named only if they were visited.
+//Exits to locked rooms, hidden exits and exits refused by Spacer.LeaveRoom are not listed.
+func (game *Game) DescribeExits() string {
+	room := game.CurrentRoom()
+	base := room.BasicRoom()
+
+	dirs := []string{}
+	for _, dir := range base.exitNames() {
+		target := game.Rooms[base.Exits[dir]]
+		if target == nil || target.BasicRoom().Locked != "" || base.isHiddenExit(dir) {
+			continue
+		}
+		dirs = append(dirs, dir)
+	}
+	refused := game.refusedExits(room, dirs)
+
+	exits := []string{}
+	for _, dir := range dirs {
+		if refused[dir] {
+			continue
+		}
+
+		target := game.Rooms[base.Exits[dir]]
+		if target.BasicRoom().IsVisited {
+			dir += " (" + base.Exits[dir] + ")"
+		}
+		exits = append(exits, dir)
+	}
+
+	if len(exits) == 0 {
+		return "There are no obvious exits."
+	}
+	return "Exits: " + strings.Join(exits, ", ")
+}
+
+//refusedExits - directions the room doesn't let the player go, LeaveRoom is asked unless the room is a LeaveChecker,
+//the world is loaded back from the save if LeaveRoom changed it
+func (game *Game) refusedExits(room Spacer, dirs []string) map[string]bool {
+	refused := make(map[string]bool)
+	if checker, ok := room.(LeaveChecker); ok {
+		for _, dir := range dirs {
+			refused[dir] = !checker.CanLeave(dir)
+		}
+		return refused
+	}
+
+	var before bytes.Buffer
+	if err := game.Save(&before); err != nil {
+		return refused
+	}
+	for _, dir := range dirs {
+		canLeave, _ := room.LeaveRoom(dir)
+		refused[dir] = !canLeave
+	}
+
+	var after bytes.Buffer
+	if err := game.Save(&after); err == nil && !bytes.Equal(before.Bytes(), after.Bytes()) {
+		game.load(&before)
+	}
+	return refused
+}
+
+//exitNames - exits of the room, compass directions first, then named exits
+func (room *Room) exitNames() []string {
+	names := []string{}
+	for _, dir := range directions {
+		if _, ok := room.Exits[dir.name]; ok {
+			names = append(names, dir.name)
+		}
+	}
+
+	named := []string{}
+	for name := range room.Exits {
+		if Direction(name) == "" {
+			named = append(named, name)
+		}
+	}
+	sort.Strings(named)
+	return append(names, named...)
+}
+
+func (room *Room) isHiddenExit(dir string) bool {
+	for _, check := range room.HiddenExits {
+		if check == dir {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+)
+
+//guardedRoom - room refusing the way south in LeaveRoom only, every refusal is counted
+type guardedRoom struct {
+	Room
+	Refusals int
+}
+
+func init() {
+	RegisterRoom("guardedRoom", func(game *Game) Spacer { return &guardedRoom{} })
+}
+
+func (room *guardedRoom) LeaveRoom(dir string) (bool, string) {
+	if dir == "south" {
+		room.Refusals++
+		return false, "The guard blocks the way."
+	}
+	return true, ""
+}
+
+func TestExitsRefusedByLeaveRoom(t *testing.T) {
+	game := &Game{Location: "Gate", Rooms: make(map[string]Spacer)}
+	gate := &guardedRoom{Room: Room{Desc: "Gate."}}
+	game.Rooms["Gate"] = gate
+	game.Rooms["Road"] = &Room{Desc: "Road."}
+	game.Rooms["Yard"] = &Room{Desc: "Yard."}
+	game.Connect("Gate", "north", "Road")
+	game.Connect("Gate", "south", "Yard")
+	game.Intro()
+
+	exits := game.DescribeExits()
+	if !strings.Contains(exits, "north") || strings.Contains(exits, "south") {
+		t.Fatalf("exits %q should list north only", exits)
+	}
+	if gate := game.Rooms["Gate"].(*guardedRoom); gate.Refusals != 0 {
+		t.Fatalf("listing exits changed the room, %d refusals", gate.Refusals)
+	}
+	if msg := Process(game, "s"); !strings.Contains(msg, "guard") {
+		t.Fatalf("s: %q", msg)
+	}
+}
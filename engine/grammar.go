@@ -0,0 +1,286 @@
+package engine
+
+import (
+	"errors"
+	"strings"
+)
+
+//Verb - grammar line of a command, Pattern has words, alternatives separated by "|" and slots:
+//
+//	"pick up [item]", "put [item] in|into|on [item]", "talk to [actor]", "ask [actor] about [topic]"
+//
+//A slot is one or more words, the last slot may be empty, so "take" asks "Take what?".
+//The verb runs Command or Action, which is an item, actor or room action by its flags.
+type Verb struct {
+	Pattern string
+	Action  *Action
+	Command func(game Adventurer, words []string) string
+}
+
+//slots known in patterns
+var slots = map[string]bool{"[item]": true, "[actor]": true, "[topic]": true, "[text]": true}
+
+type token struct {
+	words map[string]bool //alternatives, nil for a slot
+	slot  string
+}
+
+//Verbs - built-in grammar, story verbs and verbs of the story actions are tried before it
+var Verbs = []Verb{
+	{Pattern: "go|walk to [text]", Command: travel},
+	{Pattern: "go|walk [text]", Command: walk},
+	{Pattern: "look|l", Command: look},
+	{Pattern: "look|l around", Command: look},
+	{Pattern: "look|l at [item]", Action: EXAMINE},
+	{Pattern: "examine|x|search [item]", Action: EXAMINE},
+	{Pattern: "open [item]", Action: OPEN},
+	{Pattern: "close|shut [item]", Action: CLOSE},
+	{Pattern: "unlock [item] with|using [item]", Action: UNLOCK},
+	{Pattern: "unlock [item]", Action: UNLOCK},
+	{Pattern: "lock [item] with|using [item]", Action: LOCK},
+	{Pattern: "lock [item]", Action: LOCK},
+	{Pattern: "pick up [item]", Action: TAKE},
+	{Pattern: "pick [item] up", Action: TAKE},
+	{Pattern: "take|get|pick [item]", Action: TAKE},
+	{Pattern: "put down [item]", Action: DROP},
+	{Pattern: "put [item] down", Action: DROP},
+	{Pattern: "put|place [item] in|into|on|onto|inside [item]", Action: PUT},
+	{Pattern: "put|place [item]", Action: PUT},
+	{Pattern: "drop [item]", Action: DROP},
+	{Pattern: "use [item] on|with [item]", Action: USE},
+	{Pattern: "use [item]", Action: USE},
+	{Pattern: "light [item]", Action: LIGHT},
+	{Pattern: "turn|switch on [item]", Action: LIGHT},
+	{Pattern: "turn|switch [item] on", Action: LIGHT},
+	{Pattern: "extinguish|douse [item]", Action: EXTINGUISH},
+	{Pattern: "turn|switch off [item]", Action: EXTINGUISH},
+	{Pattern: "turn|switch [item] off", Action: EXTINGUISH},
+	{Pattern: "ask|tell|talk [actor] about [topic]", Action: ASK},
+	{Pattern: "talk|speak to|with [actor]", Action: ASK},
+	{Pattern: "ask|tell|talk [actor]", Action: ASK},
+	{Pattern: "give|show [item] to [actor]", Action: GIVE},
+	{Pattern: "give|show [item]", Action: GIVE},
+	{Pattern: "inventory|inv|i", Command: func(game Adventurer, words []string) string {
+		return game.ShowInventory() + game.CurrentRoom().OnAction(INVENTORY)
+	}},
+	{Pattern: "help", Command: func(game Adventurer, words []string) string {
+		return game.Help()
+	}},
+	{Pattern: "score", Command: func(game Adventurer, words []string) string {
+		return game.BasicGame().ShowScore()
+	}},
+	{Pattern: "full score", Command: func(game Adventurer, words []string) string {
+		return game.BasicGame().ShowFullScore()
+	}},
+	{Pattern: "fullscore", Command: func(game Adventurer, words []string) string {
+		return game.BasicGame().ShowFullScore()
+	}},
+	{Pattern: "map", Command: func(game Adventurer, words []string) string {
+		return game.BasicGame().ShowMap()
+	}},
+	{Pattern: "exits", Command: func(game Adventurer, words []string) string {
+		if !game.BasicGame().IsLit() {
+			return "It's too dark to see any exits."
+		}
+		return game.BasicGame().DescribeExits()
+	}}}
+
+//Patterns - grammar lines made from the action flags:
+//"name [item] syntax [item]" and "name [item]", "name [actor] syntax [topic]" and "name [actor]" or "name"
+func (action *Action) Patterns() []string {
+	object, target := "", "[item]"
+	switch {
+	case action.IsItemRequired:
+		object = " [item]"
+		if action.IsActorTarget {
+			target = "[actor]"
+		}
+	case action.IsActorRequired:
+		object, target = " [actor]", "[topic]"
+	default:
+		return []string{action.Name}
+	}
+
+	patterns := []string{}
+	if action.Syntax != "" {
+		patterns = append(patterns, action.Name+object+" "+action.Syntax+" "+target)
+	}
+	return append(patterns, action.Name+object)
+}
+
+//CheckPattern - error if the pattern is empty, starts with a slot or has unknown slots
+func CheckPattern(pattern string) error {
+	tokens := parsePattern(pattern)
+	if len(tokens) == 0 {
+		return errors.New("empty pattern")
+	}
+	if tokens[0].slot != "" {
+		return errors.New("pattern starts with a slot")
+	}
+	for i, t := range tokens {
+		if t.slot != "" && !slots[t.slot] {
+			return errors.New("unknown slot " + t.slot)
+		}
+		if t.slot != "" && i > 0 && tokens[i-1].slot != "" {
+			return errors.New("slots " + tokens[i-1].slot + " and " + t.slot + " should be separated by a word")
+		}
+	}
+	return nil
+}
+
+func parsePattern(pattern string) []token {
+	tokens := []token{}
+	for _, word := range strings.Fields(strings.ToLower(pattern)) {
+		if strings.HasPrefix(word, "[") {
+			tokens = append(tokens, token{slot: word})
+			continue
+		}
+		alternatives := make(map[string]bool)
+		for _, alternative := range strings.Split(word, "|") {
+			alternatives[alternative] = true
+		}
+		tokens = append(tokens, token{words: alternatives})
+	}
+	return tokens
+}
+
+//verbs - story verbs, verbs of the story actions and the built-in ones, in the order they are tried
+func (game *Game) verbs() []Verb {
+	result := append([]Verb{}, game.Verbs...)
+	for i := range game.Actions {
+		action := &game.Actions[i]
+		if action.IsPredefined {
+			continue
+		}
+		for _, pattern := range action.Patterns() {
+			result = append(result, Verb{Pattern: pattern, Action: action})
+		}
+	}
+	return append(result, Verbs...)
+}
+
+//AddVerb - story verb for an action: game.AddVerb("pick up [item]", TAKE)
+func (game *Game) AddVerb(pattern string, action *Action) {
+	game.Verbs = append(game.Verbs, Verb{Pattern: pattern, Action: action})
+}
+
+//match - words for the action if the command fits the pattern: the slots with the words between them.
+//Words between slots become the action syntax, so "unlock box using key" is "box with key".
+//Without syntax only the words the object parser skips are kept, so "put bottle inside box" is "bottle box".
+func (verb *Verb) match(words []string) ([]string, bool) {
+	tokens := parsePattern(verb.Pattern)
+	parts, ok := matchTokens(tokens, words)
+	if !ok {
+		return nil, false
+	}
+
+	first, last := -1, -1
+	for i, t := range tokens {
+		if t.slot != "" {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+
+	result := []string{}
+	for i := first; i >= 0 && i <= last; i++ {
+		switch {
+		case tokens[i].slot != "":
+			result = append(result, parts[i]...)
+		case verb.Action == nil:
+			result = append(result, parts[i]...)
+		case verb.Action.Syntax == "":
+			if ignore[parts[i][0]] {
+				result = append(result, parts[i]...)
+			}
+		case tokens[i-1].slot != "":
+			result = append(result, verb.Action.Syntax)
+		}
+	}
+	return result, true
+}
+
+//matchTokens - words matched by every token, a slot takes as few words as possible
+func matchTokens(tokens []token, words []string) ([][]string, bool) {
+	if len(tokens) == 0 {
+		return nil, len(words) == 0
+	}
+
+	t := tokens[0]
+	if t.slot == "" {
+		if len(words) == 0 || !t.words[words[0]] {
+			return nil, false
+		}
+		rest, ok := matchTokens(tokens[1:], words[1:])
+		if !ok {
+			return nil, false
+		}
+		return append([][]string{words[:1]}, rest...), true
+	}
+
+	if len(tokens) == 1 {
+		return [][]string{words}, true
+	}
+	for n := 1; n <= len(words); n++ {
+		if rest, ok := matchTokens(tokens[1:], words[n:]); ok {
+			return append([][]string{words[:n]}, rest...), true
+		}
+	}
+	return nil, false
+}
+
+//run - the command or the action by its flags
+func (verb *Verb) run(game Adventurer, words []string) string {
+	if verb.Command != nil {
+		return verb.Command(game, words)
+	}
+
+	action := verb.Action
+	switch {
+	case action.IsItemRequired:
+		return game.DoItemAction(words, action)
+	case action.IsActorRequired:
+		msg := game.DoActorAction(words, action)
+		if action.IsPredefined {
+			msg += game.CurrentRoom().OnAction(action)
+		}
+		return msg
+	}
+	return game.CurrentRoom().OnAction(action)
+}
+
+//look - "look", "look around"
+func look(game Adventurer, words []string) string {
+	return game.BasicGame().Look() + game.CurrentRoom().OnAction(LOOK)
+}
+
+//walk - "go north", "go ladder"
+func walk(game Adventurer, words []string) string {
+	base := game.BasicGame()
+	if len(words) == 0 {
+		return base.fail("Go where?")
+	}
+	exits := game.CurrentRoom().BasicRoom().Exits
+	if dir := Direction(words[0]); dir != "" && len(words) == 1 {
+		return game.Navigate(exits[dir], dir)
+	}
+	if name := strings.Join(words, " "); exits[name] != "" {
+		return game.Navigate(exits[name], name)
+	}
+	return base.fail("You can't go that way.")
+}
+
+//isVerb - the word starts a command: a direction or the first word of a verb
+func (game *Game) isVerb(word string) bool {
+	if Direction(word) != "" {
+		return true
+	}
+	for _, verb := range game.verbs() {
+		if tokens := parsePattern(verb.Pattern); len(tokens) > 0 && tokens[0].words[word] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package engine
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckPattern(t *testing.T) {
+	for _, pattern := range []string{"pick up [item]", "put|place [item] in|into [item]", "ask [actor] about [topic]"} {
+		if err := CheckPattern(pattern); err != nil {
+			t.Errorf("%q: %v", pattern, err)
+		}
+	}
+	for _, pattern := range []string{"", "[item] up", "take [thing]", "put [item] [item]"} {
+		if err := CheckPattern(pattern); err == nil {
+			t.Errorf("%q should be wrong", pattern)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		verb   Verb
+		input  string
+		expect []string
+	}{
+		{Verb{Pattern: "pick up [item]", Action: TAKE}, "pick up old lantern", []string{"old", "lantern"}},
+		{Verb{Pattern: "pick [item] up", Action: TAKE}, "pick lantern up", []string{"lantern"}},
+		{Verb{Pattern: "unlock [item] with|using [item]", Action: UNLOCK}, "unlock box using key", []string{"box", "with", "key"}},
+		{Verb{Pattern: "put|place [item] in|into|on|onto|inside [item]", Action: PUT}, "place bottle inside box", []string{"bottle", "box"}},
+		{Verb{Pattern: "take|get [item]", Action: TAKE}, "get", []string{}},
+		{Verb{Pattern: "take|get [item]", Action: TAKE}, "grab lantern", nil},
+		{Verb{Pattern: "look|l", Action: LOOK}, "look at box", nil},
+	}
+	for _, test := range tests {
+		got, ok := test.verb.match(strings.Fields(test.input))
+		if ok != (test.expect != nil) || (ok && !reflect.DeepEqual(got, test.expect)) {
+			t.Errorf("%q with %q: %q, %v", test.input, test.verb.Pattern, got, ok)
+		}
+	}
+}
+
+func TestActionPatterns(t *testing.T) {
+	kick := &Action{Name: "kick", IsItemRequired: true, Syntax: "with"}
+	if got := kick.Patterns(); !reflect.DeepEqual(got, []string{"kick [item] with [item]", "kick [item]"}) {
+		t.Errorf("kick: %q", got)
+	}
+	greet := &Action{Name: "greet", IsActorRequired: true}
+	if got := greet.Patterns(); !reflect.DeepEqual(got, []string{"greet [actor]"}) {
+		t.Errorf("greet: %q", got)
+	}
+	dance := &Action{Name: "dance"}
+	if got := dance.Patterns(); !reflect.DeepEqual(got, []string{"dance"}) {
+		t.Errorf("dance: %q", got)
+	}
+}
+
+func TestSynonyms(t *testing.T) {
+	for _, command := range []string{"take box", "get the box", "pick up box", "pick box up"} {
+		game := newSaveGame()
+		game.Intro()
+		if msg := Process(game, command); msg != "Taken." {
+			t.Errorf("%s: %q", command, msg)
+		}
+	}
+}
+
+func TestStoryVerb(t *testing.T) {
+	game := newSaveGame()
+	game.AddVerb("grab [item]", TAKE)
+	game.Intro()
+	if msg := Process(game, "grab box"); msg != "Taken." {
+		t.Fatalf("grab box: %q", msg)
+	}
+	if msg := Process(game, "grab"); msg != "Take what?" {
+		t.Fatalf("grab: %q", msg)
+	}
+}
+
+func TestLookAround(t *testing.T) {
+	game := newSaveGame()
+	game.Intro()
+	if msg := Process(game, "look around"); !strings.HasPrefix(msg, "Hall.") {
+		t.Fatalf("look around: %q", msg)
+	}
+}
+
+func TestDirectionWithTrailingWords(t *testing.T) {
+	game := newSaveGame()
+	game.Intro()
+	if msg := Process(game, "n foo"); msg != "I only understood you as far as wanting to go north." {
+		t.Fatalf("n foo: %q", msg)
+	}
+	if game.Location != "Hall" {
+		t.Fatalf("the player went to %s", game.Location)
+	}
+}
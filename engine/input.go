@@ -0,0 +1,41 @@
+package engine
+
+import (
+	"strings"
+)
+
+//articles skipped at the start of a command
+var articles = map[string]bool{"the": true, "a": true, "an": true}
+
+//splitCommands - "take key, unlock box with key then open box and x it." is four commands
+func splitCommands(game *Game, input string) []string {
+	commands := []string{}
+	for _, sentence := range strings.Split(input, ".") {
+		words := strings.Fields(strings.Replace(sentence, ",", " , ", -1))
+		command := []string{}
+		for i, word := range words {
+			if word == "then" || ((word == "," || word == "and") && i+1 < len(words) && game.isVerb(words[i+1])) {
+				commands = appendCommand(commands, command)
+				command = nil
+				continue
+			}
+			command = append(command, word)
+		}
+		commands = appendCommand(commands, command)
+	}
+	return commands
+}
+
+func appendCommand(commands []string, words []string) []string {
+	command := strings.Trim(strings.Replace(strings.Join(words, " "), " ,", ",", -1), ", ")
+	if command == "" {
+		return commands
+	}
+	return append(commands, command)
+}
+
+//fail - marks the command as failed, so the rest of the input line is skipped
+func (game *Game) fail(msg string) string {
+	game.failed = true
+	return msg
+}
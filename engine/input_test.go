@@ -0,0 +1,34 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitCommands(t *testing.T) {
+	game := newSaveGame()
+	tests := map[string][]string{
+		"take key, unlock box with key then open box and x it.": {"take key", "unlock box with key", "open box", "x it"},
+		"take key and bottle, n":                                {"take key and bottle", "n"},
+		"drop all except sword, key":                            {"drop all except sword, key"},
+		"look. inventory":                                       {"look", "inventory"},
+		" . , then":                                             {},
+	}
+	for input, expect := range tests {
+		if got := splitCommands(game, input); !reflect.DeepEqual(got, expect) {
+			t.Errorf("%q: %q", input, got)
+		}
+	}
+}
+
+func TestCommandsStopOnFailure(t *testing.T) {
+	game := newSaveGame()
+	game.Intro()
+	msg := Process(game, "take box then xyzzy then n")
+	if msg != "Taken.\nI don't know the word \"xyzzy\"." || game.Location != "Hall" {
+		t.Fatalf("%q in %s", msg, game.Location)
+	}
+	if game.Turns != 1 {
+		t.Fatalf("%d turns", game.Turns)
+	}
+}
@@ -16,6 +16,9 @@ type Item struct {
 	IsLocked          bool
 	IsUnbreakableName bool
 	IsUseTarget       bool
+	IsLightSource     bool
+	IsLit             bool
+	IsPlural          bool //referred as "them"
 
 	Name     string
 	AName    string
@@ -27,7 +30,7 @@ type Item struct {
 	DefaultActionDesc map[string]string
 	CanContainOnly    []string
 
-	Items []Itemer
+	Items []Itemer `json:"-"`
 }
 
 //Itemer - item interface
@@ -42,6 +45,36 @@ func (item *Item) Basic() *Item {
 	return item
 }
 
+//Flag - provides access to item flag by name: open, locked, visible, etc.
+//Returns nil for unknown flag.
+func (item *Item) Flag(name string) *bool {
+	switch name {
+	case "decoration":
+		return &item.IsDecoration
+	case "surface":
+		return &item.IsSurface
+	case "container":
+		return &item.IsContainer
+	case "pickable":
+		return &item.IsPickable
+	case "visible":
+		return &item.IsVisible
+	case "disabled":
+		return &item.IsDisabled
+	case "open":
+		return &item.IsOpen
+	case "locked":
+		return &item.IsLocked
+	case "lightsource":
+		return &item.IsLightSource
+	case "lit":
+		return &item.IsLit
+	case "plural":
+		return &item.IsPlural
+	}
+	return nil
+}
+
 //NameWithArticle - provides item full name
 func (item *Item) NameWithArticle() string {
 	if item.AName != "" {
@@ -66,10 +99,18 @@ func (item *Item) OnAction(action *Action, target Itemer) (string, string) {
 		return item.Take()
 	case PUT:
 		return item.Put(target)
+	case DROP:
+		return item.Drop()
 	case UNLOCK:
 		return item.Unlock(target), item.Location
+	case LOCK:
+		return item.Lock(target), item.Location
 	case USE:
 		return item.Use(target), item.Location
+	case LIGHT:
+		return item.Light(), item.Location
+	case EXTINGUISH:
+		return item.Extinguish(), item.Location
 	}
 
 	return "You can't " + action.Name + " " + item.NameWithArticle() + ".", item.Location
@@ -182,6 +223,19 @@ func (item *Item) Take() (string, string) {
 	return "Taken.", "inventory"
 }
 
+//Drop item in the current room
+func (item *Item) Drop() (string, string) {
+	if item.Location != "inventory" {
+		return "You are not holding " + item.NameWithArticle() + ".", item.Location
+	}
+
+	msg, ok := item.DefaultActionDesc["drop"]
+	if !ok {
+		msg = "Dropped."
+	}
+	return msg, Here
+}
+
 //Put item into inventory
 func (item *Item) Put(target Itemer) (string, string) {
 	if item.Location != "inventory" {
@@ -232,11 +286,41 @@ func (item *Item) Unlock(target Itemer) string {
 	if key.Location != "inventory" {
 		return "You are not holding " + key.NameWithArticle() + "."
 	}
+	if item.KeyName != "" && key.Name != item.KeyName {
+		return "It doesn't fit."
+	}
 
 	item.IsLocked = false
 	return "Unlocked."
 }
 
+//Lock container with key
+func (item *Item) Lock(target Itemer) string {
+	if !item.IsContainer {
+		return "You can't lock it."
+	}
+	if item.IsLocked {
+		return "It's already locked."
+	}
+	if item.IsOpen {
+		return "You need to close it first."
+	}
+	if target == nil {
+		return "You need a key to lock it."
+	}
+
+	key := target.Basic()
+	if key.Location != "inventory" {
+		return "You are not holding " + key.NameWithArticle() + "."
+	}
+	if item.KeyName != "" && key.Name != item.KeyName {
+		return "It doesn't fit."
+	}
+
+	item.IsLocked = true
+	return "Locked."
+}
+
 //Use item
 func (item *Item) Use(target Itemer) string {
 
@@ -263,3 +347,39 @@ func (item *Item) Use(target Itemer) string {
 
 	return msg
 }
+
+//Light - turns light source on
+func (item *Item) Light() string {
+	msg, ok := item.DefaultActionDesc["light"]
+
+	if !item.IsLightSource {
+		if ok {
+			return msg
+		}
+		return "You can't light it."
+	}
+
+	if item.IsLit {
+		return "It's already lit."
+	}
+
+	item.IsLit = true
+	if !ok {
+		msg = "Lit."
+	}
+	return msg
+}
+
+//Extinguish - turns light source off
+func (item *Item) Extinguish() string {
+	if !item.IsLightSource || !item.IsLit {
+		return "It's not lit."
+	}
+
+	item.IsLit = false
+	msg, ok := item.DefaultActionDesc["extinguish"]
+	if !ok {
+		msg = "Extinguished."
+	}
+	return msg
+}
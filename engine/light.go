@@ -0,0 +1,65 @@
+package engine
+
+//DarkDesc - description of a dark room, if Room.DarkDesc is not set
+const DarkDesc = "It is pitch dark. You can't see a thing."
+
+//IsLit - checks if the player can see in the current room.
+//Room is lit if it's not dark or there is a lit light source in the room or in the inventory.
+func (game *Game) IsLit() bool {
+	room := game.CurrentRoom().BasicRoom()
+	if !room.IsDark {
+		return true
+	}
+
+	for _, item := range visibleItems(append(game.Inventory, room.Items...), false, true) {
+		if item.Basic().IsLightSource && item.Basic().IsLit {
+			return true
+		}
+	}
+	return false
+}
+
+//scope - items the player can reach, only inventory in darkness
+func (game *Game) scope() []Itemer {
+	if !game.IsLit() {
+		return visibleItems(game.Inventory, false, true)
+	}
+	room := game.CurrentRoom().BasicRoom()
+	return visibleItems(append(game.Inventory, room.Items...), false, true)
+}
+
+//Look - room description, if the player can see it, without spending a turn
+func (game *Game) Look() string {
+	room := game.CurrentRoom()
+	if !game.IsLit() {
+		return room.BasicRoom().darkness()
+	}
+	if game.ShowExits {
+		return room.Look() + "\n" + game.DescribeExits()
+	}
+	return room.Look()
+}
+
+//lightChanged - describes the room after the light was turned on or off
+func (game *Game) lightChanged(wasLit bool) string {
+	isLit := game.IsLit()
+	if wasLit == isLit {
+		return ""
+	}
+
+	room := game.CurrentRoom()
+	if !isLit {
+		return "\n" + room.BasicRoom().darkness()
+	}
+	if !room.BasicRoom().IsVisited {
+		return "\n\n" + game.EnterRoom()
+	}
+	return "\n\n" + game.Look()
+}
+
+func (room *Room) darkness() string {
+	if room.DarkDesc != "" {
+		return room.DarkDesc
+	}
+	return DarkDesc
+}
@@ -0,0 +1,186 @@
+package engine
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+//maxMapLabel - room names are cut to this length on the map
+const maxMapLabel = 16
+
+type mapPoint struct {
+	x, y int
+}
+
+//worldMap - visited rooms laid out on a grid by compass exits
+type worldMap struct {
+	rooms   map[string]mapPoint
+	stubs   map[mapPoint]bool //unvisited rooms next to visited ones
+	links   [][2]mapPoint
+	current string
+
+	min, max mapPoint
+}
+
+//layoutMap places the current room at the center and the other visited rooms around it.
+//Rooms which don't fit the grid (like the ones reachable only by "up" or through a twisty passage) are skipped.
+func (game *Game) layoutMap() *worldMap {
+	m := &worldMap{
+		rooms:   map[string]mapPoint{game.Location: {}},
+		stubs:   make(map[mapPoint]bool),
+		current: game.Location}
+
+	taken := map[mapPoint]bool{{}: true}
+	queue := []string{game.Location}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		room := game.Rooms[name].BasicRoom()
+		for _, dir := range directions {
+			target := game.Rooms[room.Exits[dir.name]]
+			if target == nil || !target.BasicRoom().IsVisited || (dir.dx == 0 && dir.dy == 0) {
+				continue
+			}
+			if _, ok := m.rooms[room.Exits[dir.name]]; ok {
+				continue
+			}
+
+			point := mapPoint{m.rooms[name].x + dir.dx, m.rooms[name].y + dir.dy}
+			if taken[point] {
+				continue
+			}
+			taken[point] = true
+			m.rooms[room.Exits[dir.name]] = point
+			queue = append(queue, room.Exits[dir.name])
+		}
+	}
+
+	for name, from := range m.rooms {
+		room := game.Rooms[name].BasicRoom()
+		for _, dir := range directions {
+			target := game.Rooms[room.Exits[dir.name]]
+			if target == nil || (dir.dx == 0 && dir.dy == 0) {
+				continue
+			}
+
+			to := mapPoint{from.x + dir.dx, from.y + dir.dy}
+			if !target.BasicRoom().IsVisited && !taken[to] {
+				m.stubs[to] = true
+			} else if point, ok := m.rooms[room.Exits[dir.name]]; !ok || point != to {
+				continue
+			}
+			m.links = append(m.links, [2]mapPoint{from, to})
+		}
+	}
+
+	for _, point := range m.rooms {
+		m.extend(point)
+	}
+	for point := range m.stubs {
+		m.extend(point)
+	}
+	return m
+}
+
+func (m *worldMap) extend(point mapPoint) {
+	if point.x < m.min.x {
+		m.min.x = point.x
+	}
+	if point.y < m.min.y {
+		m.min.y = point.y
+	}
+	if point.x > m.max.x {
+		m.max.x = point.x
+	}
+	if point.y > m.max.y {
+		m.max.y = point.y
+	}
+}
+
+//labels - room names by position, the current room is marked with "*"
+func (m *worldMap) labels() map[mapPoint]string {
+	labels := make(map[mapPoint]string)
+	for name, point := range m.rooms {
+		label := name
+		if runes := []rune(label); len(runes) > maxMapLabel {
+			label = string(runes[:maxMapLabel])
+		}
+		if name == m.current {
+			label = "*" + label
+		}
+		labels[point] = label
+	}
+	for point := range m.stubs {
+		labels[point] = "?"
+	}
+	return labels
+}
+
+//ShowMap - text map of visited rooms
+func (game *Game) ShowMap() string {
+	m := game.layoutMap()
+	labels := m.labels()
+
+	width := 1
+	for _, label := range labels {
+		if length := utf8.RuneCountInString(label); length+2 > width {
+			width = length + 2
+		}
+	}
+	const gap = 3
+
+	columns := (m.max.x-m.min.x+1)*(width+gap) - gap
+	rows := (m.max.y-m.min.y)*2 + 1
+	canvas := make([][]rune, rows)
+	for i := range canvas {
+		canvas[i] = []rune(strings.Repeat(" ", columns))
+	}
+
+	position := func(point mapPoint) (int, int) {
+		return (point.y - m.min.y) * 2, (point.x - m.min.x) * (width + gap)
+	}
+
+	for point, label := range labels {
+		row, col := position(point)
+		if label != "?" {
+			label = "[" + label + "]"
+		}
+		text := []rune(label)
+		copy(canvas[row][col+width/2-len(text)/2:], text)
+	}
+
+	for _, link := range m.links {
+		from, to := link[0], link[1]
+		if to.x < from.x || (to.x == from.x && to.y < from.y) {
+			from, to = to, from
+		}
+		row, col := position(from)
+		center := col + width/2
+
+		switch {
+		case to.y == from.y:
+			copy(canvas[row][col+width:], []rune(strings.Repeat("-", gap)))
+		case to.x == from.x:
+			canvas[row+1][center] = '|'
+		case to.y < from.y:
+			mark(canvas, row-1, col+width+gap/2, '/')
+		default:
+			mark(canvas, row+1, col+width+gap/2, '\\')
+		}
+	}
+
+	lines := []string{}
+	for _, line := range canvas {
+		lines = append(lines, strings.TrimRight(string(line), " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+//mark draws diagonal link, crossing links become "X"
+func mark(canvas [][]rune, row int, col int, char rune) {
+	if canvas[row][col] != ' ' && canvas[row][col] != char {
+		char = 'X'
+	}
+	canvas[row][col] = char
+}
@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//map image sizes in pixels
+const (
+	mapScale   = 2
+	mapPadding = 8
+	mapGap     = 40
+	mapMargin  = 20
+)
+
+var (
+	mapBackground = color.RGBA{54, 57, 63, 255}
+	mapRoom       = color.RGBA{79, 84, 92, 255}
+	mapCurrent    = color.RGBA{67, 181, 129, 255}
+	mapLine       = color.RGBA{220, 221, 222, 255}
+)
+
+//MapImage - map of visited rooms as image, use png.Encode to save it
+func (game *Game) MapImage() image.Image {
+	m := game.layoutMap()
+	labels := m.labels()
+
+	chars := 1
+	for _, label := range labels {
+		if length := utf8.RuneCountInString(label); length > chars {
+			chars = length
+		}
+	}
+
+	boxWidth := chars*(glyphWidth+1)*mapScale + mapPadding*2
+	boxHeight := glyphHeight*mapScale + mapPadding*2
+	width := (m.max.x-m.min.x+1)*(boxWidth+mapGap) - mapGap + mapMargin*2
+	height := (m.max.y-m.min.y+1)*(boxHeight+mapGap) - mapGap + mapMargin*2
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{mapBackground}, image.Point{}, draw.Src)
+
+	box := func(point mapPoint) image.Rectangle {
+		x := mapMargin + (point.x-m.min.x)*(boxWidth+mapGap)
+		y := mapMargin + (point.y-m.min.y)*(boxHeight+mapGap)
+		return image.Rect(x, y, x+boxWidth, y+boxHeight)
+	}
+	center := func(point mapPoint) image.Point {
+		rect := box(point)
+		return image.Pt((rect.Min.X+rect.Max.X)/2, (rect.Min.Y+rect.Max.Y)/2)
+	}
+
+	for _, link := range m.links {
+		drawLine(img, center(link[0]), center(link[1]), mapLine)
+	}
+
+	for point, label := range labels {
+		rect := box(point)
+		if label == "?" {
+			//unvisited room is just a question mark at the end of the link
+			rect = image.Rect(center(point).X-boxHeight/2, rect.Min.Y, center(point).X+boxHeight/2, rect.Max.Y)
+			draw.Draw(img, rect, &image.Uniform{mapBackground}, image.Point{}, draw.Src)
+		} else {
+			fill := mapRoom
+			if strings.HasPrefix(label, "*") {
+				fill = mapCurrent
+				label = label[1:]
+			}
+			draw.Draw(img, rect, &image.Uniform{mapLine}, image.Point{}, draw.Src)
+			draw.Draw(img, rect.Inset(mapScale), &image.Uniform{fill}, image.Point{}, draw.Src)
+		}
+
+		textWidth := utf8.RuneCountInString(label)*(glyphWidth+1)*mapScale - mapScale
+		drawText(img, label, image.Pt(center(point).X-textWidth/2, center(point).Y-glyphHeight*mapScale/2), mapLine)
+	}
+
+	return img
+}
+
+func drawText(img *image.RGBA, text string, at image.Point, c color.Color) {
+	for i, char := range []rune(text) {
+		glyph, ok := glyphs[unicode.ToUpper(char)]
+		if !ok {
+			glyph = glyphs['?']
+		}
+
+		left := at.X + i*(glyphWidth+1)*mapScale
+		for y, row := range glyph {
+			for x, pixel := range row {
+				if pixel == ' ' {
+					continue
+				}
+				dot := image.Rect(left+x*mapScale, at.Y+y*mapScale, left+(x+1)*mapScale, at.Y+(y+1)*mapScale)
+				draw.Draw(img, dot, &image.Uniform{c}, image.Point{}, draw.Src)
+			}
+		}
+	}
+}
+
+//drawLine - Bresenham line, mapScale pixels thick
+func drawLine(img *image.RGBA, from image.Point, to image.Point, c color.Color) {
+	dx, dy := abs(to.X-from.X), -abs(to.Y-from.Y)
+	sx, sy := sign(to.X-from.X), sign(to.Y-from.Y)
+	err := dx + dy
+
+	for x, y := from.X, from.Y; ; {
+		dot := image.Rect(x, y, x+mapScale, y+mapScale)
+		draw.Draw(img, dot, &image.Uniform{c}, image.Point{}, draw.Src)
+		if x == to.X && y == to.Y {
+			return
+		}
+		e := err * 2
+		if e >= dy {
+			err += dy
+			x += sx
+		}
+		if e <= dx {
+			err += dx
+			y += sy
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
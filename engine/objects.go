@@ -0,0 +1,196 @@
+package engine
+
+import (
+	"strings"
+)
+
+//findObjects - several direct objects: "all", "all from box", "all except sword", "key and bottle", "them".
+//ok is false if there is a single object.
+func (game *Game) findObjects(words []string, items []Itemer, action *Action) ([]Itemer, []string, string, bool) {
+	words = splitLists(words)
+
+	idx := 0
+	for idx < len(words) && ignore[words[idx]] {
+		idx++
+	}
+	if idx < len(words) && (words[idx] == "all" || words[idx] == "everything") {
+		return game.findAll(words[idx+1:], items, action)
+	}
+	if objects, rest, msg, ok := game.resolveThem(words, items); ok {
+		return objects, rest, msg, true
+	}
+
+	objects := []Itemer{}
+	rest := words
+	for {
+		msg, item, next := game.findTarget(rest, items, false)
+		if msg != "" {
+			if len(objects) == 0 {
+				return nil, nil, "", false //let the single object parser report it
+			}
+			return nil, nil, msg, true
+		}
+		objects = append(objects, item)
+		rest = next
+
+		if len(rest) == 0 || rest[0] != "and" {
+			break
+		}
+		rest = rest[1:]
+	}
+
+	if len(objects) < 2 {
+		return nil, nil, "", false
+	}
+	return objects, rest, "", true
+}
+
+//findAll - "all" with "from" and "except" parts, items which make no sense for the action are skipped
+func (game *Game) findAll(words []string, items []Itemer, action *Action) ([]Itemer, []string, string, bool) {
+	candidates := game.allCandidates(action)
+
+	for len(words) > 0 {
+		switch words[0] {
+		case "from":
+			msg, source, rest := game.findTarget(words[1:], items, false)
+			if msg != "" {
+				return nil, nil, msg, true
+			}
+			candidates = reachable(source.Basic().Items)
+			words = rest
+			continue
+		case "except", "but":
+			words = words[1:]
+			for len(words) > 0 {
+				excluded, object := findItemsInList(words, items)
+				if len(object) == 0 {
+					return nil, nil, "You don't see any " + strings.Join(words, " ") + " here.", true
+				}
+				candidates = without(candidates, excluded)
+				words = words[len(object):]
+
+				if len(words) == 0 || words[0] != "and" {
+					break
+				}
+				words = words[1:]
+			}
+			continue
+		}
+		break
+	}
+
+	objects := []Itemer{}
+	for _, item := range candidates {
+		if isSuitable(item, action) {
+			objects = append(objects, item)
+		}
+	}
+
+	if len(objects) == 0 {
+		return nil, nil, "There is nothing to " + action.Name + ".", true
+	}
+	return objects, words, "", true
+}
+
+//allCandidates - what "all" means for the action
+func (game *Game) allCandidates(action *Action) []Itemer {
+	switch action {
+	case DROP, PUT, GIVE:
+		return visibleItems(game.Inventory, false, false)
+	}
+
+	if !game.IsLit() {
+		if action == TAKE {
+			return nil
+		}
+		return reachable(game.Inventory)
+	}
+
+	room := game.CurrentRoom().BasicRoom()
+	if action == TAKE {
+		return reachable(room.Items)
+	}
+	return reachable(append(game.Inventory, room.Items...))
+}
+
+//isSuitable - "all" skips decorations, persons, doors and items which can't be taken or aren't held
+func isSuitable(i Itemer, action *Action) bool {
+	item := i.Basic()
+	if item.IsDecoration {
+		return false
+	}
+	if _, ok := i.(Actor); ok {
+		return false
+	}
+	if _, ok := i.(Doorer); ok {
+		return false
+	}
+
+	switch action {
+	case TAKE:
+		return item.IsPickable && item.Location != "inventory"
+	case DROP, PUT, GIVE:
+		return item.Location == "inventory"
+	}
+	return true
+}
+
+//doEach - the action for every object, each one is reported separately: "bottle: Taken."
+func (game *Game) doEach(objects []Itemer, words []string, items []Itemer, action *Action) string {
+	responses := []string{}
+	for _, item := range objects {
+		msg := game.doItemAction(item, words, items, action)
+		if game.failed {
+			//the rest of the command is wrong, it's the same for every object
+			return msg
+		}
+		responses = append(responses, item.Basic().Name+": "+msg)
+	}
+	return strings.Join(responses, "\n")
+}
+
+//reachable - visible items without the ones in closed containers
+func reachable(items []Itemer) []Itemer {
+	result := []Itemer{}
+	for _, i := range items {
+		item := i.Basic()
+		if !item.IsVisible || item.IsDisabled {
+			continue
+		}
+		result = append(result, i)
+		if !item.IsContainer || item.IsOpen {
+			result = append(result, reachable(item.Items)...)
+		}
+	}
+	return result
+}
+
+func without(items []Itemer, excluded []Itemer) []Itemer {
+	result := []Itemer{}
+	for _, item := range items {
+		isExcluded := false
+		for _, check := range excluded {
+			if item == check {
+				isExcluded = true
+				break
+			}
+		}
+		if !isExcluded {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+//splitLists - "key, bottle and sword" is "key and bottle and sword"
+func splitLists(words []string) []string {
+	result := []string{}
+	for _, word := range words {
+		if strings.HasSuffix(word, ",") {
+			result = append(result, strings.TrimSuffix(word, ","), "and")
+			continue
+		}
+		result = append(result, word)
+	}
+	return result
+}
@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"testing"
+)
+
+//newObjectsGame - Hall with a key, a bottle, a sword, a statue, a wall and an open box with a coin
+func newObjectsGame() *Game {
+	game := &Game{Location: "Hall", Rooms: make(map[string]Spacer)}
+	items := []Itemer{}
+	for _, name := range []string{"key", "bottle", "sword"} {
+		items = append(items, &Item{Name: name, Location: "Hall", IsVisible: true, IsPickable: true})
+	}
+	coin := &Item{Name: "coin", Location: "box", IsVisible: true, IsPickable: true}
+	items = append(items,
+		&Item{Name: "statue", Location: "Hall", IsVisible: true},
+		&Item{Name: "wall", Location: "Hall", IsVisible: true, IsPickable: true, IsDecoration: true},
+		&Item{Name: "box", Location: "Hall", IsVisible: true, IsContainer: true, IsOpen: true, Items: []Itemer{coin}})
+	game.Rooms["Hall"] = &Room{Desc: "Hall.", Items: items}
+	game.Intro()
+	return game
+}
+
+func TestThemAfterSeveralObjects(t *testing.T) {
+	game := newObjectsGame()
+	if msg := Process(game, "take key and bottle"); msg != "key: Taken.\nbottle: Taken." {
+		t.Fatalf("take key and bottle: %q", msg)
+	}
+	if msg := Process(game, "drop them"); msg != "key: Dropped.\nbottle: Dropped." {
+		t.Fatalf("drop them: %q", msg)
+	}
+	if len(game.Inventory) != 0 {
+		t.Fatalf("%d items left in the inventory", len(game.Inventory))
+	}
+}
+
+func TestAll(t *testing.T) {
+	game := newObjectsGame()
+	if msg := Process(game, "take all"); msg != "key: Taken.\nbottle: Taken.\nsword: Taken.\ncoin: Taken." {
+		t.Fatalf("take all: %q", msg)
+	}
+	if msg := Process(game, "take all"); msg != "There is nothing to take." {
+		t.Fatalf("take all again: %q", msg)
+	}
+	if msg := Process(game, "drop all except sword and key"); msg != "bottle: Dropped.\ncoin: Dropped." {
+		t.Fatalf("drop all except sword and key: %q", msg)
+	}
+	if msg := Process(game, "drop all but sword"); msg != "key: Dropped." {
+		t.Fatalf("drop all but sword: %q", msg)
+	}
+}
+
+func TestAllFrom(t *testing.T) {
+	game := newObjectsGame()
+	if msg := Process(game, "take all from box"); msg != "coin: Taken." {
+		t.Fatalf("take all from box: %q", msg)
+	}
+	if msg := Process(game, "take all except xyzzy"); msg != "You don't see any xyzzy here." {
+		t.Fatalf("take all except xyzzy: %q", msg)
+	}
+}
@@ -1,9 +1,5 @@
 package engine
 
-import (
-	"math/rand"
-)
-
 //Actor interface
 type Actor interface {
 	Itemer
@@ -19,6 +15,9 @@ type Person struct {
 	Hello          string
 	NameEx         string
 	IsKnown        bool
+	Gender         string //"male" or "female" makes the person "him" or "her", otherwise "them"
+
+	game *Game //set before each topic, answers are picked with its random source
 }
 
 //Topic for interaction with Actor
@@ -36,7 +35,7 @@ func (person *Person) OnTopic(topic *Topic, action *Action, item Itemer) string
 	if topic == nil {
 		count := len(person.DefaultAnswers[action.Name])
 		if count > 0 {
-			return person.DefaultAnswers[action.Name][rand.Intn(count)]
+			return person.DefaultAnswers[action.Name][person.pick(count)]
 		}
 		return action.DefaultTopicAnswer
 	}
@@ -44,14 +43,14 @@ func (person *Person) OnTopic(topic *Topic, action *Action, item Itemer) string
 	if topic.IsUsed {
 		count := len(topic.RepeatAnswers)
 		if count > 0 {
-			return topic.RepeatAnswers[rand.Intn(count)]
+			return topic.RepeatAnswers[person.pick(count)]
 		}
 	}
 
 	topic.IsUsed = true
 	count := len(topic.Answers)
 	if count > 0 {
-		return topic.Answers[rand.Intn(count)]
+		return topic.Answers[person.pick(count)]
 	}
 
 	return "ERROR: topic has no answers!"
@@ -91,3 +90,17 @@ func (person *Person) Examine() string {
 
 	return "You see " + person.NameWithArticle() + "."
 }
+
+//pick - index of random answer, the first one if the person is used outside of the game
+func (person *Person) pick(count int) int {
+	if person.game == nil {
+		return 0
+	}
+	return person.game.Random(count)
+}
+
+//talk - passes the topic to the actor, all the answers come from the game random source
+func (game *Game) talk(actor Actor, topic *Topic, action *Action, item Itemer) string {
+	actor.BasicPerson().game = game
+	return actor.OnTopic(topic, action, item)
+}
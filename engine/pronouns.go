@@ -0,0 +1,137 @@
+package engine
+
+import (
+	"strings"
+)
+
+//pronouns the parser understands, they refer to the last mentioned item or person
+var pronouns = map[string]bool{
+	"it":   true,
+	"them": true,
+	"him":  true,
+	"her":  true}
+
+//pronounOf - "it", "them" for plural items and persons without gender, "him" or "her"
+func pronounOf(i Itemer) string {
+	if actor, ok := i.(Actor); ok {
+		switch actor.BasicPerson().Gender {
+		case "male":
+			return "him"
+		case "female":
+			return "her"
+		}
+		return "them"
+	}
+	if i.Basic().IsPlural {
+		return "them"
+	}
+	return "it"
+}
+
+//refer - remembers the direct object or the person for pronouns, so "unlock it with key" doesn't change "it".
+//Names are kept, so undo doesn't break the links, Load forgets them.
+func (game *Game) refer(items ...Itemer) {
+	if game.pronouns == nil {
+		game.pronouns = make(map[string]string)
+	}
+	for _, item := range items {
+		if item != nil {
+			game.pronouns[pronounOf(item)] = item.Basic().Name
+			if pronounOf(item) == "them" {
+				game.them = nil
+			}
+		}
+	}
+}
+
+//referAll - remembers the objects of "take key and bottle", so "drop them" drops both
+func (game *Game) referAll(objects []Itemer) {
+	game.them = []string{}
+	for _, item := range objects {
+		game.them = append(game.them, item.Basic().Name)
+	}
+}
+
+//resolveThem - the objects of the last command with several objects if the first word is "them",
+//ok is false if it's not or there were no such command
+func (game *Game) resolveThem(words []string, items []Itemer) (objects []Itemer, rest []string, msg string, ok bool) {
+	idx := 0
+	for idx < len(words) && ignore[words[idx]] {
+		idx++
+	}
+	if idx == len(words) || words[idx] != "them" || len(game.them) == 0 {
+		return nil, nil, "", false
+	}
+
+	for _, name := range game.them {
+		found := false
+		for _, item := range items {
+			if item.Basic().Name == name {
+				objects = append(objects, item)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, nil, "You don't see any " + name + " here.", true
+		}
+	}
+	return objects, words[idx+1:], "", true
+}
+
+//findTarget - findTarget which understands pronouns
+func (game *Game) findTarget(words []string, items []Itemer, optional bool) (string, Itemer, []string) {
+	if msg, item, rest, ok := game.resolve(words, items); ok {
+		return msg, item, rest
+	}
+
+	msg, item, rest := findTarget(words, items, optional)
+	if possible, object := findItemsInList(words, items); len(possible) > 1 && len(object) > 0 {
+		return game.ask(msg, &question{strings.Fields(game.Input), object, possible}), nil, nil
+	}
+	return msg, item, rest
+}
+
+//findActor - findActor which understands pronouns
+func (game *Game) findActor(words []string, actors []Actor) (string, Actor, []string) {
+	items := []Itemer{}
+	for _, actor := range actors {
+		items = append(items, actor)
+	}
+	if msg, item, rest, ok := game.resolve(words, items); ok {
+		if item == nil {
+			return msg, nil, nil
+		}
+		return msg, item.(Actor), rest
+	}
+
+	msg, actor, rest := findActor(words, actors)
+	if possible, object := findItemsInList(words, items); len(possible) > 1 && len(object) > 0 {
+		return game.ask(msg, &question{strings.Fields(game.Input), object, possible}), nil, nil
+	}
+	return msg, actor, rest
+}
+
+//resolve - the item the first word refers to, ok is false if it's not a pronoun
+func (game *Game) resolve(words []string, items []Itemer) (msg string, item Itemer, rest []string, ok bool) {
+	idx := 0
+	for idx < len(words) && ignore[words[idx]] {
+		idx++
+	}
+	if idx == len(words) || !pronouns[words[idx]] {
+		return "", nil, nil, false
+	}
+
+	pronoun := words[idx]
+	name := game.pronouns[pronoun]
+	if name == "" {
+		return "I'm not sure what \"" + pronoun + "\" refers to.", nil, nil, true
+	}
+
+	for _, item := range items {
+		if item.Basic().Name == name {
+			return "", item, words[idx+1:], true
+		}
+	}
+	return "You don't see any " + name + " here.", nil, nil, true
+}
@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"strings"
+)
+
+//question - the parser asked to clarify the command, the next reply may complete it
+type question struct {
+	input      []string //words of the command
+	phrase     []string //ambiguous words, nil if an object is missing
+	candidates []Itemer //items matching the phrase
+}
+
+//ask - fails the command and keeps the question for the next reply
+func (game *Game) ask(msg string, q *question) string {
+	game.question = q
+	return game.fail(msg)
+}
+
+//askFor - missing object, the reply is added to the command: "Take what?" - "lantern"
+func (game *Game) askFor(msg string, command string) string {
+	return game.ask(msg, &question{input: strings.Fields(command)})
+}
+
+//askForTarget - "Unlock with what?" or "Where do you want to put it?", the reply is added after the syntax word
+func (game *Game) askForTarget(action *Action, isSyntaxFound bool) string {
+	if action.Syntax == "" {
+		return game.askFor("Where do you want to "+action.Name+" it?", game.Input)
+	}
+
+	command := game.Input
+	if !isSyntaxFound {
+		command += " " + action.Syntax
+	}
+	return game.askFor(strings.Title(action.Name)+" "+action.Syntax+" what?", command)
+}
+
+//answer - the command completed by the reply, false if the reply looks like a new command
+func (q *question) answer(game *Game, reply string) (string, bool) {
+	words := strings.Fields(reply)
+	if len(words) == 0 || game.isVerb(words[0]) || metaCommands[reply] ||
+		reply == "undo" || reply == "restart" || game.IsFinished() {
+		return "", false
+	}
+
+	if q.phrase == nil {
+		return strings.Join(append(append([]string{}, q.input...), words...), " "), true
+	}
+
+	possible, object := findItemsInList(words, q.candidates)
+	switch {
+	case len(object) == 0:
+		return "", false
+	case len(possible) == 1:
+		//"steel" - the phrase becomes the full name
+		words = strings.Fields(strings.ToLower(possible[0].Basic().Name))
+	default:
+		//still ambiguous, the parser will ask again with less candidates
+		known := strings.Join(q.phrase, " ")
+		refined := []string{}
+		for _, word := range words {
+			if !strings.Contains(" "+known+" ", " "+word+" ") {
+				refined = append(refined, word)
+			}
+		}
+		words = append(refined, q.phrase...)
+	}
+	return strings.Join(replaceWords(q.input, q.phrase, words), " "), true
+}
+
+//replaceWords - the first occurrence of phrase in words is replaced
+func replaceWords(words []string, phrase []string, replacement []string) []string {
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		if strings.Join(words[i:i+len(phrase)], " ") == strings.Join(phrase, " ") {
+			result := append([]string{}, words[:i]...)
+			result = append(result, replacement...)
+			return append(result, words[i+len(phrase):]...)
+		}
+	}
+	return words
+}
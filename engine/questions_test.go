@@ -0,0 +1,57 @@
+package engine
+
+import (
+	"testing"
+)
+
+//newQuestionsGame - Hall with two swords, a locked box and its key, an old man and a young man, Study to the north
+func newQuestionsGame() *Game {
+	game := newSaveGame()
+	game.Rooms["Hall"].BasicRoom().Items = []Itemer{
+		&Item{Name: "steel sword", Location: "Hall", IsVisible: true, IsPickable: true},
+		&Item{Name: "silver sword", Location: "Hall", IsVisible: true, IsPickable: true},
+		&Item{Name: "box", Location: "Hall", IsVisible: true, IsContainer: true, IsLocked: true, KeyName: "key"},
+		&Item{Name: "key", Location: "Hall", IsVisible: true, IsPickable: true},
+		&Person{Item: Item{Name: "old man", Location: "Hall", IsVisible: true}, Hello: "Hi, I'm old."},
+		&Person{Item: Item{Name: "young man", Location: "Hall", IsVisible: true}, Hello: "Hi, I'm young."}}
+	game.Intro()
+	return game
+}
+
+func TestAnswerDisambiguation(t *testing.T) {
+	game := newQuestionsGame()
+	if msg := Process(game, "take sword"); msg != "What sword do you mean: steel sword or silver sword?" {
+		t.Fatalf("take sword: %q", msg)
+	}
+	if msg := Process(game, "steel"); msg != "Taken." {
+		t.Fatalf("steel: %q", msg)
+	}
+	if len(game.Inventory) != 1 || game.Inventory[0].Basic().Name != "steel sword" {
+		t.Fatal("the steel sword isn't taken")
+	}
+	if msg := Process(game, "talk to man"); msg != "Whom do you mean: old man or young man?" {
+		t.Fatalf("talk to man: %q", msg)
+	}
+	if Process(game, "young"); game.pronouns["them"] != "young man" {
+		t.Fatalf("talked to %q", game.pronouns["them"])
+	}
+}
+
+func TestAnswerMissingObject(t *testing.T) {
+	game := newQuestionsGame()
+	Process(game, "take key")
+	if msg := Process(game, "unlock box"); msg != "Unlock with what?" {
+		t.Fatalf("unlock box: %q", msg)
+	}
+	if msg := Process(game, "key"); msg != "Unlocked." {
+		t.Fatalf("key: %q", msg)
+	}
+}
+
+func TestNewCommandInsteadOfAnswer(t *testing.T) {
+	game := newQuestionsGame()
+	Process(game, "take sword")
+	if Process(game, "n"); game.Location != "Study" || game.question != nil {
+		t.Fatal("the reply wasn't a new command")
+	}
+}
@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"math/rand"
+	"time"
+)
+
+//countingSource remembers how many numbers were taken, so the exact state can be saved and restored
+type countingSource struct {
+	rand.Source
+	draws int
+}
+
+func (src *countingSource) Int63() int64 {
+	src.draws++
+	return src.Source.Int63()
+}
+
+//SetSeed - restarts the game random source, the same seed and commands give the same responses
+func (game *Game) SetSeed(seed int64) {
+	game.seed = seed
+	game.source = &countingSource{Source: rand.NewSource(seed)}
+	game.random = rand.New(game.source)
+}
+
+//Seed - seed of the game random source, it's chosen by time unless SetSeed was called
+func (game *Game) Seed() int64 {
+	game.initRandom()
+	return game.seed
+}
+
+//Random - random number from 0 to n-1. Stories should use it instead of math/rand,
+//so every game has its own repeatable sequence.
+func (game *Game) Random(n int) int {
+	game.initRandom()
+	return game.random.Intn(n)
+}
+
+func (game *Game) initRandom() {
+	if game.random == nil {
+		game.SetSeed(time.Now().UnixNano())
+	}
+}
+
+//restoreRandom - the source at the saved position
+func (game *Game) restoreRandom(seed int64, draws int) {
+	game.SetSeed(seed)
+	for i := 0; i < draws; i++ {
+		game.source.Source.Int63()
+	}
+	game.source.draws = draws
+}
@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"reflect"
+)
+
+var (
+	constructors = make(map[string]func(game *Game) interface{})
+	typeNames    = make(map[reflect.Type]string)
+)
+
+func init() {
+	RegisterItem("item", func(game *Game) Itemer { return &Item{} })
+	RegisterActor("person", func(game *Game) Actor { return &Person{} })
+	RegisterRoom("room", func(game *Game) Spacer { return &Room{} })
+}
+
+//RegisterItem - registers named constructor of custom item type,
+//it should be called from init, before any story is loaded
+func RegisterItem(name string, create func(game *Game) Itemer) {
+	register(name, func(game *Game) interface{} { return create(game) })
+}
+
+//RegisterActor - registers named constructor of custom actor type
+func RegisterActor(name string, create func(game *Game) Actor) {
+	register(name, func(game *Game) interface{} { return create(game) })
+}
+
+//RegisterRoom - registers named constructor of custom room type
+func RegisterRoom(name string, create func(game *Game) Spacer) {
+	register(name, func(game *Game) interface{} { return create(game) })
+}
+
+//NewItem - creates item of registered type, nil if type is unknown
+func NewItem(name string, game *Game) Itemer {
+	item, _ := newObject(name, game).(Itemer)
+	return item
+}
+
+//NewActor - creates actor of registered type, nil if type is unknown or it's not an actor
+func NewActor(name string, game *Game) Actor {
+	actor, _ := newObject(name, game).(Actor)
+	return actor
+}
+
+//NewRoom - creates room of registered type, nil if type is unknown
+func NewRoom(name string, game *Game) Spacer {
+	room, _ := newObject(name, game).(Spacer)
+	return room
+}
+
+//TypeName - registered name of the object type
+func TypeName(obj interface{}) string {
+	return typeNames[reflect.TypeOf(obj)]
+}
+
+func register(name string, create func(game *Game) interface{}) {
+	constructors[name] = create
+	typeNames[reflect.TypeOf(create(&Game{}))] = name
+}
+
+func newObject(name string, game *Game) interface{} {
+	create, ok := constructors[name]
+	if !ok {
+		return nil
+	}
+	return create(game)
+}
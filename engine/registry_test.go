@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRegisteredTypes(t *testing.T) {
+	if _, ok := NewItem("lamp", &Game{}).(*lamp); !ok {
+		t.Error("lamp isn't created by its constructor")
+	}
+	if NewItem("unknown", &Game{}) != nil {
+		t.Error("unknown type is created")
+	}
+	if NewActor("item", &Game{}) != nil {
+		t.Error("item is created as an actor")
+	}
+	if name := TypeName(&lamp{}); name != "lamp" {
+		t.Errorf("type name %q", name)
+	}
+}
+
+func TestLoadCreatesRegisteredTypes(t *testing.T) {
+	game := newSaveGame()
+	game.Inventory = []Itemer{&lamp{Item: Item{Name: "lamp", Location: "inventory", IsVisible: true}, oil: 2}}
+
+	var buf bytes.Buffer
+	if err := game.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	//the fresh game has no lamp, it's made by the registered constructor
+	loaded := newSaveGame()
+	if err := loaded.Load(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if lamp, ok := loaded.Inventory[0].(*lamp); !ok || lamp.oil != 2 {
+		t.Fatalf("inventory %#v", loaded.Inventory[0])
+	}
+}
+
+func TestLoadUnknownType(t *testing.T) {
+	game := newSaveGame()
+	var buf bytes.Buffer
+	if err := game.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	doc := strings.Replace(buf.String(), `"type": "room"`, `"type": "castle"`, 1)
+	if err := newSaveGame().Load(strings.NewReader(doc)); err == nil || !strings.Contains(err.Error(), "castle") {
+		t.Fatalf("error %v", err)
+	}
+}
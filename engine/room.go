@@ -2,20 +2,22 @@ package engine
 
 //Room - basic room structure
 type Room struct {
-	Desc  string
-	North string
-	South string
-	East  string
-	West  string
+	Desc        string
+	Exits       map[string]string //rooms by direction: north, up, in, or any name like "ladder"
+	Blocked     map[string]string //messages for directions without exits, like "The river is too deep."
+	HiddenExits []string          //secret exits, not listed by "exits"
 
 	Locked string
 
 	IsVisited bool
+	IsDark    bool   //player needs a light source to see anything
+	DarkDesc  string //shown instead of the description in darkness
 
-	Items []Itemer
+	Items []Itemer `json:"-"`
 }
 
-//Spacer - room interface
+//Spacer - room interface. LeaveRoom gets the full direction ("north", "up") or the exit name ("ladder"),
+//short forms like "n" are expanded before.
 type Spacer interface {
 	BasicRoom() *Room
 	LeaveRoom(dir string) (bool, string)
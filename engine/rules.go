@@ -0,0 +1,209 @@
+package engine
+
+import (
+	"strings"
+)
+
+//Phase - when rule is applied
+type Phase int
+
+//Rule phases
+const (
+	Before  Phase = iota //runs before the action, can stop it
+	Instead              //replaces the action
+	After                //runs after the action
+)
+
+//Rule - declarative reaction on action, like
+//"instead of take gold skull when pedestal has fewer than 2 items: finish the game"
+type Rule struct {
+	Phase  Phase
+	Action string //action name, "go" for navigation
+	Object string //item or actor name, direction for "go", empty for any
+	Target string //second item or actor name, destination room for "go", empty for any
+	Topic  string //word from the topic vocab for actor actions, empty for any
+	Room   string //current room, empty for any
+	Stop   bool   //before rule prevents the action
+
+	When []Condition
+	Then []Effect
+}
+
+//Condition - all set fields should match
+type Condition struct {
+	Item    string //item checked by In, Flag and Fewer
+	In      string //item is located in the room, item or "inventory"
+	Flag    string //item flag is set: open, locked, visible, etc.
+	Fewer   int    //item holds fewer than N items
+	Visited string //room was visited
+	Not     bool   //negates the whole condition
+}
+
+//Effect - all set fields are applied in order
+type Effect struct {
+	Print  string
+	Item   string //item changed by MoveTo, Remove, Set and Clear
+	MoveTo string //room, item or "inventory"
+	Remove bool   //removes item from the game
+	Set    string //flag to set
+	Clear  string //flag to clear
+	Award  string //award name
+	Finish string //ending id
+}
+
+type ruleContext struct {
+	action string
+	object string
+	target string
+	topic  string
+}
+
+//withRules wraps action into rules phases
+func (game *Game) withRules(ctx *ruleContext, do func() string) string {
+	before, stop := game.applyRules(Before, ctx)
+	if stop {
+		return before
+	}
+
+	instead, stop := game.applyRules(Instead, ctx)
+	if stop {
+		return joinText(before, instead)
+	}
+
+	msg := joinText(before, do())
+	after, _ := game.applyRules(After, ctx)
+	return joinText(msg, after)
+}
+
+//applyRules runs matching rules of the phase, returns their text and true if action should be stopped
+func (game *Game) applyRules(phase Phase, ctx *ruleContext) (string, bool) {
+	msg := ""
+	stop := false
+
+	for i := range game.Rules {
+		rule := &game.Rules[i]
+		if rule.Phase != phase || !game.matchRule(rule, ctx) {
+			continue
+		}
+
+		for _, effect := range rule.Then {
+			msg = joinText(msg, game.applyEffect(&effect))
+		}
+		stop = stop || rule.Phase == Instead || rule.Stop
+	}
+
+	return msg, stop
+}
+
+func (game *Game) matchRule(rule *Rule, ctx *ruleContext) bool {
+	if rule.Action != ctx.action ||
+		!matchName(rule.Object, ctx.object) ||
+		!matchName(rule.Target, ctx.target) ||
+		!matchName(rule.Room, game.Location) {
+		return false
+	}
+
+	if rule.Topic != "" && !matchWord(rule.Topic, ctx.topic) {
+		return false
+	}
+
+	for _, condition := range rule.When {
+		if game.checkCondition(&condition) == condition.Not {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (game *Game) checkCondition(condition *Condition) bool {
+	if condition.Visited != "" {
+		room := game.Rooms[condition.Visited]
+		if room == nil || !room.BasicRoom().IsVisited {
+			return false
+		}
+	}
+
+	if condition.Item == "" {
+		return true
+	}
+
+	found := game.FindItem(condition.Item)
+	if found == nil {
+		return false
+	}
+	item := found.Basic()
+
+	if condition.In != "" && !strings.EqualFold(condition.In, item.Location) {
+		return false
+	}
+
+	if condition.Flag != "" {
+		flag := item.Flag(condition.Flag)
+		if flag == nil || !*flag {
+			return false
+		}
+	}
+
+	if condition.Fewer > 0 && len(item.Items) >= condition.Fewer {
+		return false
+	}
+
+	return true
+}
+
+func (game *Game) applyEffect(effect *Effect) string {
+	if effect.Item != "" {
+		if item := game.FindItem(effect.Item); item != nil {
+			if effect.MoveTo != "" {
+				game.MoveItem(item, effect.MoveTo)
+			}
+			if effect.Remove {
+				game.MoveItem(item, "")
+			}
+			if flag := item.Basic().Flag(effect.Set); flag != nil {
+				*flag = true
+			}
+			if flag := item.Basic().Flag(effect.Clear); flag != nil {
+				*flag = false
+			}
+		}
+	}
+
+	if effect.Award != "" {
+		game.GrantAward(effect.Award)
+	}
+
+	if effect.Finish != "" {
+		game.Finish(effect.Finish)
+	}
+
+	return effect.Print
+}
+
+func matchName(pattern string, name string) bool {
+	return pattern == "" || strings.EqualFold(pattern, name)
+}
+
+func matchWord(word string, vocab string) bool {
+	for _, check := range strings.Fields(vocab) {
+		if strings.EqualFold(word, check) {
+			return true
+		}
+	}
+	return strings.EqualFold(word, vocab)
+}
+
+func joinText(a string, b string) string {
+	if a == "" || b == "" {
+		return a + b
+	}
+	return a + "\n" + b
+}
+
+func nameOf(item Itemer) string {
+	if item == nil {
+		return ""
+	}
+	return item.Basic().Name
+}
@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+)
+
+//newRulesGame - Hall with a skull on a pedestal, Study to the north, arrows kill the player
+//who takes the skull from the empty pedestal and nobody passes north without the skull
+func newRulesGame(weights int) *Game {
+	game := newSaveGame()
+	pedestal := &Item{Name: "pedestal", Location: "Hall", IsVisible: true, IsSurface: true}
+	pedestal.Items = []Itemer{&Item{Name: "skull", Location: "pedestal", IsVisible: true, IsPickable: true}}
+	for i := 0; i < weights; i++ {
+		pedestal.Items = append(pedestal.Items, &Item{Name: "weight", Location: "pedestal", IsVisible: true})
+	}
+	game.Rooms["Hall"].BasicRoom().Items = []Itemer{pedestal}
+	game.Endings = []Ending{{ID: "death", Kind: Lose}}
+	game.Rules = []Rule{
+		{Phase: Instead, Action: "take", Object: "skull",
+			When: []Condition{{Item: "pedestal", Fewer: 2}},
+			Then: []Effect{{Print: "Arrows fly from the walls!", Finish: "death"}}},
+		{Phase: After, Action: "take", Object: "skull", Then: []Effect{{Print: "It's cold."}}},
+		{Phase: Before, Action: "go", Object: "north", Stop: true,
+			When: []Condition{{Item: "skull", In: "inventory", Not: true}},
+			Then: []Effect{{Print: "A voice says: bring me the skull."}}}}
+	game.Intro()
+	return game
+}
+
+func TestInsteadRule(t *testing.T) {
+	game := newRulesGame(0)
+	if msg := Process(game, "take skull"); !strings.HasPrefix(msg, "Arrows fly from the walls!") {
+		t.Fatalf("take skull: %q", msg)
+	}
+	if game.Ending == nil || game.Ending.ID != "death" || len(game.Inventory) != 0 {
+		t.Fatal("the instead rule didn't replace taking the skull")
+	}
+}
+
+func TestRulesNotMatched(t *testing.T) {
+	game := newRulesGame(1)
+	if msg := Process(game, "n"); msg != "A voice says: bring me the skull." || game.Location != "Hall" {
+		t.Fatalf("n: %q", msg)
+	}
+	if msg := Process(game, "take skull"); msg != "Taken.\nIt's cold." {
+		t.Fatalf("take skull: %q", msg)
+	}
+	if Process(game, "n"); game.Location != "Study" {
+		t.Fatal("the before rule stopped the player with the skull")
+	}
+}
@@ -0,0 +1,287 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+//SaveVersion - version of the saved game format
+const SaveVersion = 1
+
+//Persistent - custom types with private fields expose them through State,
+//it should return a pointer, so the same value can be used for loading
+type Persistent interface {
+	State() interface{}
+}
+
+type savedGame struct {
+	Version   int                     `json:"version"`
+	Location  string                  `json:"location"`
+	Ending    string                  `json:"ending,omitempty"`
+	Turns     int                     `json:"turns"`
+	Seed      int64                   `json:"seed"`
+	Draws     int                     `json:"draws"`
+	Inventory []*savedObject          `json:"inventory"`
+	Rooms     map[string]*savedObject `json:"rooms"`
+	Timers    []*savedTimer           `json:"timers,omitempty"`
+	Awarded   []string                `json:"awarded,omitempty"`
+}
+
+type savedTimer struct {
+	Name   string `json:"name"`
+	Owner  string `json:"owner,omitempty"`
+	Turns  int    `json:"turns,omitempty"`
+	IsFuse bool   `json:"fuse,omitempty"`
+}
+
+type savedObject struct {
+	Type  string          `json:"type"`
+	Data  json.RawMessage `json:"data"`
+	State json.RawMessage `json:"state,omitempty"`
+	Items []*savedObject  `json:"items,omitempty"`
+}
+
+//Save - writes the whole world state as JSON document
+func (game *Game) Save(w io.Writer) error {
+	game.learnTypes()
+
+	doc := savedGame{
+		Version:  SaveVersion,
+		Location: game.Location,
+		Turns:    game.Turns,
+		Seed:     game.Seed(),
+		Draws:    game.source.draws,
+		Awarded:  game.awarded,
+		Rooms:    make(map[string]*savedObject)}
+
+	if game.Ending != nil {
+		doc.Ending = game.Ending.ID
+	}
+
+	for _, timer := range game.timers {
+		doc.Timers = append(doc.Timers, &savedTimer{timer.Name, timer.Owner, timer.Turns, timer.IsFuse})
+	}
+
+	var err error
+	if doc.Inventory, err = saveItems(game.Inventory); err != nil {
+		return err
+	}
+
+	for name, room := range game.Rooms {
+		saved, err := saveObject(room)
+		if err != nil {
+			return err
+		}
+		if saved.Items, err = saveItems(room.BasicRoom().Items); err != nil {
+			return err
+		}
+		doc.Rooms[name] = saved
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(&doc)
+}
+
+//Load - restores the world state written by Save.
+//Custom types are created with registered constructors, unregistered ones are recreated
+//from the objects of the same type found in the game, so it should be called on a freshly created story instance.
+//The parser forgets the question, pronouns and score notifications of the previous state.
+func (game *Game) Load(r io.Reader) error {
+	if err := game.load(r); err != nil {
+		return err
+	}
+	game.question = nil
+	game.pronouns = nil
+	game.them = nil
+	game.failed = false
+	game.notifications = nil
+	return nil
+}
+
+//load - restores the world state, the parser state is kept
+func (game *Game) load(r io.Reader) error {
+	game.Begin()
+	game.learnTypes()
+
+	var doc savedGame
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return err
+	}
+
+	if doc.Version != SaveVersion {
+		return fmt.Errorf("unsupported save version %d", doc.Version)
+	}
+
+	inventory, err := game.loadItems(doc.Inventory)
+	if err != nil {
+		return err
+	}
+
+	rooms := make(map[string]Spacer)
+	for name, saved := range doc.Rooms {
+		obj, err := game.loadObject(saved)
+		if err != nil {
+			return err
+		}
+		room, ok := obj.(Spacer)
+		if !ok {
+			return fmt.Errorf("type %s is not a room", saved.Type)
+		}
+		if room.BasicRoom().Items, err = game.loadItems(saved.Items); err != nil {
+			return err
+		}
+		rooms[name] = room
+	}
+
+	var ending *Ending
+	if doc.Ending != "" {
+		if ending = game.findEnding(doc.Ending); ending == nil {
+			return fmt.Errorf("unknown ending %s", doc.Ending)
+		}
+	}
+
+	timers := []*Timer{}
+	for _, saved := range doc.Timers {
+		run := game.handler(saved.Name)
+		if run == nil {
+			return fmt.Errorf("unknown timer %s", saved.Name)
+		}
+		timers = append(timers, &Timer{saved.Name, saved.Owner, saved.Turns, saved.IsFuse, run})
+	}
+
+	game.Location = doc.Location
+	game.Ending = ending
+	game.Turns = doc.Turns
+	game.Inventory = inventory
+	game.Rooms = rooms
+	game.linkDoors()
+	game.timers = timers
+	game.awarded = doc.Awarded
+	game.restoreRandom(doc.Seed, doc.Draws)
+	return nil
+}
+
+func saveItems(items []Itemer) ([]*savedObject, error) {
+	result := []*savedObject{}
+	for _, item := range items {
+		saved, err := saveObject(item)
+		if err != nil {
+			return nil, err
+		}
+		if saved.Items, err = saveItems(item.Basic().Items); err != nil {
+			return nil, err
+		}
+		result = append(result, saved)
+	}
+	return result, nil
+}
+
+func saveObject(obj interface{}) (*savedObject, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	saved := &savedObject{Type: typeName(obj), Data: data}
+
+	if persistent, ok := obj.(Persistent); ok {
+		if saved.State, err = json.Marshal(persistent.State()); err != nil {
+			return nil, err
+		}
+	}
+
+	return saved, nil
+}
+
+func (game *Game) loadItems(list []*savedObject) ([]Itemer, error) {
+	result := []Itemer{}
+	for _, saved := range list {
+		obj, err := game.loadObject(saved)
+		if err != nil {
+			return nil, err
+		}
+		item, ok := obj.(Itemer)
+		if !ok {
+			return nil, fmt.Errorf("type %s is not an item", saved.Type)
+		}
+		if item.Basic().Items, err = game.loadItems(saved.Items); err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+func (game *Game) loadObject(saved *savedObject) (interface{}, error) {
+	obj := newObject(saved.Type, game)
+
+	if obj == nil {
+		prototype, ok := game.prototypes[saved.Type]
+		if !ok {
+			return nil, fmt.Errorf("unknown type %s", saved.Type)
+		}
+
+		//copy private fields (like links to the game) from the prototype,
+		//everything exported comes from the saved data
+		value := reflect.New(reflect.TypeOf(prototype).Elem())
+		value.Elem().Set(reflect.ValueOf(prototype).Elem())
+		resetExported(value.Elem())
+		obj = value.Interface()
+	}
+
+	if err := json.Unmarshal(saved.Data, obj); err != nil {
+		return nil, fmt.Errorf("%s: %v", saved.Type, err)
+	}
+
+	if persistent, ok := obj.(Persistent); ok && saved.State != nil {
+		if err := json.Unmarshal(saved.State, persistent.State()); err != nil {
+			return nil, fmt.Errorf("%s: %v", saved.Type, err)
+		}
+	}
+
+	return obj, nil
+}
+
+//learnTypes remembers every type used in the world, so it can be recreated later
+func (game *Game) learnTypes() {
+	if game.prototypes == nil {
+		game.prototypes = make(map[string]interface{})
+	}
+
+	for _, room := range game.Rooms {
+		game.prototypes[typeName(room)] = room
+		game.learnItemTypes(room.BasicRoom().Items)
+	}
+	game.learnItemTypes(game.Inventory)
+}
+
+func (game *Game) learnItemTypes(items []Itemer) {
+	for _, item := range items {
+		game.prototypes[typeName(item)] = item
+		game.learnItemTypes(item.Basic().Items)
+	}
+}
+
+func typeName(obj interface{}) string {
+	if name := TypeName(obj); name != "" {
+		return name
+	}
+	return reflect.TypeOf(obj).Elem().String()
+}
+
+func resetExported(value reflect.Value) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.PkgPath != "" {
+			continue //unexported
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			resetExported(value.Field(i))
+			continue
+		}
+		value.Field(i).Set(reflect.Zero(field.Type))
+	}
+}
@@ -0,0 +1,114 @@
+package engine
+
+import (
+	"bytes"
+	"testing"
+)
+
+//lamp - custom item with private state saved through Persistent
+type lamp struct {
+	Item
+	oil int
+}
+
+func init() {
+	RegisterItem("lamp", func(game *Game) Itemer { return &lamp{} })
+}
+
+func (lamp *lamp) State() interface{} {
+	return &lamp.oil
+}
+
+//newSaveGame - Hall with a box, Study to the north
+func newSaveGame() *Game {
+	game := &Game{Location: "Hall", Rooms: make(map[string]Spacer)}
+	game.Rooms["Hall"] = &Room{Desc: "Hall.", Items: []Itemer{&Item{Name: "box", IsVisible: true, IsPickable: true}}}
+	game.Rooms["Study"] = &Room{Desc: "Study."}
+	game.Connect("Hall", "north", "Study")
+	return game
+}
+
+//newWorldGame - newSaveGame with a coin in the box, a lamp, a witch and a door to the Study
+func newWorldGame() *Game {
+	game := newSaveGame()
+	hall := game.Rooms["Hall"].BasicRoom()
+	box := hall.Items[0].Basic()
+	box.IsContainer, box.IsOpen = true, true
+	box.Items = []Itemer{&Item{Name: "coin", Location: "box", IsVisible: true, IsPickable: true}}
+	game.Inventory = []Itemer{&lamp{Item: Item{Name: "lamp", Location: "inventory", IsVisible: true}, oil: 3}}
+	hall.Items = append(hall.Items, &Person{Item: Item{Name: "witch", Location: "Hall", IsVisible: true},
+		Topics: []*Topic{{Action: "ask", Vocab: "name", Answers: []string{"Melissa."}}}})
+	game.AddDoor(&Door{Item: Item{Name: "door", IsVisible: true, IsLocked: true}, Connects: [2]string{"Hall", "Study"}})
+	return game
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	game := newWorldGame()
+	game.Intro()
+	hall := game.Rooms["Hall"].BasicRoom()
+	hall.Items[0].Basic().IsOpen = false
+	witch := hall.Items[1].(*Person)
+	witch.Topics[0].IsUsed, witch.NameEx, witch.IsKnown = true, "Melissa", true
+	game.Inventory[0].(*lamp).oil = 1
+	door := hall.Items[2].(*Door)
+	door.IsLocked, door.IsOpen = false, true
+	game.Location = "Study"
+
+	var saved bytes.Buffer
+	if err := game.Save(&saved); err != nil {
+		t.Fatal(err)
+	}
+	loaded := newWorldGame()
+	if err := loaded.Load(bytes.NewReader(saved.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+
+	var again bytes.Buffer
+	if err := loaded.Save(&again); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(saved.Bytes(), again.Bytes()) {
+		t.Fatalf("the loaded world differs:\n%s\n%s", saved.String(), again.String())
+	}
+
+	if loaded.Location != "Study" {
+		t.Errorf("location %q", loaded.Location)
+	}
+	if oil := loaded.Inventory[0].(*lamp).oil; oil != 1 {
+		t.Errorf("the lamp has %d oil", oil)
+	}
+	hall = loaded.Rooms["Hall"].BasicRoom()
+	if box := hall.Items[0].Basic(); box.IsOpen || len(box.Items) != 1 || box.Items[0].Basic().Name != "coin" {
+		t.Errorf("the box isn't closed with the coin inside")
+	}
+	if witch := hall.Items[1].(*Person); !witch.Topics[0].IsUsed || witch.NameEx != "Melissa" {
+		t.Errorf("the witch forgot the conversation")
+	}
+	door = hall.Items[2].(*Door)
+	if door.IsLocked || !door.IsOpen || loaded.Rooms["Study"].BasicRoom().Items[0] != Itemer(door) {
+		t.Errorf("the door isn't open and shared by both rooms")
+	}
+}
+
+func TestLoadResetsParser(t *testing.T) {
+	game := newSaveGame()
+	game.Intro()
+	Process(game, "examine box")
+	if msg := Process(game, "take"); msg != "Take what?" {
+		t.Fatalf("take: %q", msg)
+	}
+
+	var buf bytes.Buffer
+	if err := game.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := game.Load(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if game.question != nil || game.pronouns != nil || game.failed || game.notifications != nil {
+		t.Fatal("the parser state survived Load")
+	}
+	if msg := Process(game, "box"); msg == "Taken." {
+		t.Fatal("the question was answered after Load")
+	}
+}
@@ -0,0 +1,117 @@
+package engine
+
+import (
+	"strconv"
+	"strings"
+)
+
+//Award - named achievement, granted once
+type Award struct {
+	Name   string
+	Points int
+	Desc   string //for full score, like "unlocking the box"
+}
+
+//GrantAward - gives points for the award, returns false if award is unknown or already granted
+func (game *Game) GrantAward(name string) bool {
+	award := game.findAward(name)
+	if award == nil || game.IsAwarded(name) {
+		return false
+	}
+
+	game.awarded = append(game.awarded, name)
+	game.notifications = append(game.notifications,
+		"[Your score has just gone up by "+points(award.Points)+".]")
+	return true
+}
+
+//IsAwarded - checks if award was granted
+func (game *Game) IsAwarded(name string) bool {
+	for _, check := range game.awarded {
+		if check == name {
+			return true
+		}
+	}
+	return false
+}
+
+//Score - current score
+func (game *Game) Score() int {
+	score := 0
+	for _, name := range game.awarded {
+		if award := game.findAward(name); award != nil {
+			score += award.Points
+		}
+	}
+	return score
+}
+
+//MaxScore - sum of all awards
+func (game *Game) MaxScore() int {
+	score := 0
+	for _, award := range game.Awards {
+		score += award.Points
+	}
+	return score
+}
+
+//ShowScore - score with the number of turns
+func (game *Game) ShowScore() string {
+	return "You have scored " + strconv.Itoa(game.Score()) + " out of a possible " + strconv.Itoa(game.MaxScore()) +
+		", in " + strconv.Itoa(game.Turns) + turns(game.Turns) + "."
+}
+
+//ShowFullScore - score with all granted awards
+func (game *Game) ShowFullScore() string {
+	if len(game.awarded) == 0 {
+		return game.ShowScore()
+	}
+
+	msg := []string{"The score was made up as follows:"}
+	for _, name := range game.awarded {
+		award := game.findAward(name)
+		if award == nil {
+			continue
+		}
+		desc := award.Desc
+		if desc == "" {
+			desc = award.Name
+		}
+		msg = append(msg, "  "+points(award.Points)+" for "+desc)
+	}
+
+	return strings.Join(msg, "\n") + "\n\n" + game.ShowScore()
+}
+
+func (game *Game) findAward(name string) *Award {
+	for i := range game.Awards {
+		if game.Awards[i].Name == name {
+			return &game.Awards[i]
+		}
+	}
+	return nil
+}
+
+//notify - messages collected during the turn, like score changes
+func (game *Game) notify() string {
+	if len(game.notifications) == 0 {
+		return ""
+	}
+	msg := "\n" + strings.Join(game.notifications, "\n")
+	game.notifications = nil
+	return msg
+}
+
+func points(count int) string {
+	if count == 1 || count == -1 {
+		return strconv.Itoa(count) + " point"
+	}
+	return strconv.Itoa(count) + " points"
+}
+
+func turns(count int) string {
+	if count == 1 {
+		return " turn"
+	}
+	return " turns"
+}
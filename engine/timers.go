@@ -0,0 +1,115 @@
+package engine
+
+//Timer - daemon runs every turn, fuse runs once after some turns.
+//Timer with owner (room or item name) is active only while the room is current,
+//or the item is in the current room or in the inventory.
+type Timer struct {
+	Name   string
+	Owner  string
+	Turns  int //turns left for fuse
+	IsFuse bool
+	Run    func(game *Game) string
+}
+
+var timerHandlers = make(map[string]func(game *Game) string)
+
+//RegisterTimer - registers timer handler by name, so timers started during the game
+//can be restored by Load. Handler is used by AddDaemon and AddFuse if run is nil.
+func RegisterTimer(name string, run func(game *Game) string) {
+	timerHandlers[name] = run
+}
+
+//AddDaemon - starts timer which runs every turn, replaces timer with the same name
+func (game *Game) AddDaemon(name string, owner string, run func(game *Game) string) {
+	game.addTimer(&Timer{Name: name, Owner: owner, Run: run})
+}
+
+//AddFuse - starts timer which runs once after the given number of turns
+func (game *Game) AddFuse(name string, owner string, turns int, run func(game *Game) string) {
+	game.addTimer(&Timer{Name: name, Owner: owner, Turns: turns, IsFuse: true, Run: run})
+}
+
+//StopTimer - stops daemon or fuse
+func (game *Game) StopTimer(name string) {
+	for idx, timer := range game.timers {
+		if timer.Name == name {
+			game.timers = append(game.timers[:idx], game.timers[idx+1:]...)
+			return
+		}
+	}
+}
+
+//FindTimer - returns active timer, nil if it's not found
+func (game *Game) FindTimer(name string) *Timer {
+	for _, timer := range game.timers {
+		if timer.Name == name {
+			return timer
+		}
+	}
+	return nil
+}
+
+func (game *Game) addTimer(timer *Timer) {
+	if timer.Run == nil {
+		timer.Run = timerHandlers[timer.Name]
+	}
+	if game.handlers == nil {
+		game.handlers = make(map[string]func(game *Game) string)
+	}
+	game.handlers[timer.Name] = timer.Run
+
+	game.StopTimer(timer.Name)
+	game.timers = append(game.timers, timer)
+}
+
+//handler - timer function by name, for restoring saved timers
+func (game *Game) handler(name string) func(game *Game) string {
+	if run, ok := game.handlers[name]; ok {
+		return run
+	}
+	return timerHandlers[name]
+}
+
+//tick - next turn, returns output of the timers
+func (game *Game) tick() string {
+	game.Turns++
+	if game.IsFinished() {
+		return ""
+	}
+
+	msg := ""
+	for _, timer := range append([]*Timer{}, game.timers...) {
+		if !game.inScope(timer.Owner) {
+			continue
+		}
+
+		if timer.IsFuse {
+			timer.Turns--
+			if timer.Turns > 0 {
+				continue
+			}
+			game.StopTimer(timer.Name)
+		}
+
+		if timer.Run != nil {
+			msg = joinText(msg, timer.Run(game))
+		}
+	}
+
+	if msg == "" {
+		return ""
+	}
+	return "\n" + msg
+}
+
+func (game *Game) inScope(name string) bool {
+	if name == "" || name == game.Location {
+		return true
+	}
+
+	room := game.CurrentRoom().BasicRoom()
+	inList := func(item Itemer, list *[]Itemer, idx int) bool {
+		return item.Basic().Name == name
+	}
+	return walkList(&game.Inventory, inList) || walkList(&room.Items, inList)
+}
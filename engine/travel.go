@@ -0,0 +1,103 @@
+package engine
+
+import (
+	"strings"
+)
+
+//travel - "go to <room>", walks the shortest way through visited rooms.
+//Every step but the last one is a separate turn, travel stops if the way is blocked or a timer has something to say.
+func travel(game Adventurer, words []string) string {
+	base := game.BasicGame()
+	if len(words) == 0 {
+		return base.fail("Go where?")
+	}
+
+	name := base.findRoomName(strings.Join(words, " "))
+	if name == "" {
+		return base.fail("You don't know the way to " + strings.Join(words, " ") + ".")
+	}
+	if name == base.Location {
+		return base.fail("You are already here.")
+	}
+
+	path := base.findPath(base.Location, name)
+	if path == nil {
+		return base.fail("You don't know the way to " + name + ".")
+	}
+
+	taken := []string{}
+	summary := func(msg string) string {
+		if len(taken) == 0 {
+			return msg
+		}
+		return "(" + strings.Join(taken, ", ") + ")\n" + msg
+	}
+
+	for i, dir := range path {
+		next := base.CurrentRoom().BasicRoom().Exits[dir]
+		msg := game.Navigate(next, dir)
+		if base.Location != next {
+			return summary(msg)
+		}
+		taken = append(taken, dir)
+
+		if base.IsFinished() || i == len(path)-1 {
+			return summary(msg)
+		}
+
+		if events := base.tick(); events != "" {
+			return summary(msg + events)
+		}
+	}
+
+	return ""
+}
+
+//findRoomName - visited room by name, case insensitive
+func (game *Game) findRoomName(text string) string {
+	text = strings.TrimPrefix(text, "the ")
+	for name, room := range game.Rooms {
+		if strings.EqualFold(name, text) && (room.BasicRoom().IsVisited || name == game.Location) {
+			return name
+		}
+	}
+	return ""
+}
+
+//findPath - directions of the shortest way through visited rooms, nil if there is no way
+func (game *Game) findPath(from string, to string) []string {
+	type step struct {
+		room string
+		dir  string
+	}
+
+	previous := map[string]step{from: {}}
+	queue := []string{from}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if current == to {
+			path := []string{}
+			for current != from {
+				path = append([]string{previous[current].dir}, path...)
+				current = previous[current].room
+			}
+			return path
+		}
+
+		room := game.Rooms[current].BasicRoom()
+		for _, dir := range room.exitNames() {
+			next := room.Exits[dir]
+			target := game.Rooms[next]
+			if _, ok := previous[next]; ok || target == nil || !target.BasicRoom().IsVisited {
+				continue
+			}
+			previous[next] = step{current, dir}
+			queue = append(queue, next)
+		}
+	}
+
+	return nil
+}
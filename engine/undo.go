@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"bytes"
+)
+
+//DefaultUndoDepth - number of turns kept for undo, if Game.UndoDepth is not set
+const DefaultUndoDepth = 10
+
+//Undo - rolls back the previous turn, returns false if there is nothing to undo
+func (game *Game) Undo() bool {
+	count := len(game.history)
+	if count == 0 {
+		return false
+	}
+
+	snapshot := game.history[count-1]
+	if err := game.load(bytes.NewReader(snapshot)); err != nil {
+		return false
+	}
+
+	game.history = game.history[:count-1]
+	return true
+}
+
+//snapshot - serialized world state, nil if undo is disabled
+func (game *Game) snapshot() []byte {
+	if game.UndoDepth < 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := game.Save(&buf); err != nil {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+//remember the state before the turn, unless the turn changed nothing
+func (game *Game) remember(snapshot []byte) {
+	if snapshot == nil || bytes.Equal(snapshot, game.snapshot()) {
+		return
+	}
+
+	depth := game.UndoDepth
+	if depth == 0 {
+		depth = DefaultUndoDepth
+	}
+
+	game.history = append(game.history, snapshot)
+	if len(game.history) > depth {
+		game.history = game.history[len(game.history)-depth:]
+	}
+}
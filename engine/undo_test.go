@@ -0,0 +1,36 @@
+package engine
+
+import (
+	"testing"
+)
+
+func TestUndoDepth(t *testing.T) {
+	game := newSaveGame()
+	game.UndoDepth = 2
+	game.Intro()
+	for _, command := range []string{"take box", "n", "drop box"} {
+		Process(game, command)
+	}
+	//the failed command changes nothing, so it isn't remembered
+	Process(game, "xyzzy")
+
+	if !game.Undo() || len(game.Inventory) != 1 {
+		t.Fatal("the box isn't back in the inventory")
+	}
+	if !game.Undo() || game.Location != "Hall" {
+		t.Fatalf("the player is in the %s", game.Location)
+	}
+	if game.Undo() {
+		t.Fatal("more turns undone than the depth")
+	}
+}
+
+func TestUndoDisabled(t *testing.T) {
+	game := newSaveGame()
+	game.UndoDepth = -1
+	game.Intro()
+	Process(game, "take box")
+	if game.Undo() {
+		t.Fatal("undo with negative depth")
+	}
+}
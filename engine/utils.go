@@ -1,7 +1,6 @@
 package engine
 
 import (
-	"regexp"
 	"strings"
 )
 
@@ -19,6 +18,16 @@ var ignore = map[string]bool{
 	"about": true,
 	"with":  true}
 
+//hasWord - text has the word separated by spaces
+func hasWord(text string, word string) bool {
+	for _, w := range strings.Split(text, " ") {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
+
 func notifyAboutVisibleItems(items []Itemer, location string) string {
 	msg := []string{}
 	actors := []string{}
@@ -83,7 +92,7 @@ func findItemsInList(words []string, items []Itemer) ([]Itemer, []string) {
 			match := false
 
 			if item.IsUnbreakableName {
-				match, _ = regexp.MatchString("(^| )"+word+"( |$)", strings.ToLower(item.Vocab))
+				match = hasWord(strings.ToLower(item.Vocab), word)
 				t := strings.Join(words[idx:], " ")
 				if len(target) > 0 {
 					t = strings.Join(target, " ") + " " + t
@@ -91,8 +100,7 @@ func findItemsInList(words []string, items []Itemer) ([]Itemer, []string) {
 				match = match || strings.HasPrefix(t, strings.ToLower(item.Name))
 
 			} else {
-				match, _ = regexp.MatchString("(^| )"+word+"( |$)",
-					strings.ToLower(item.Vocab+" "+item.Name))
+				match = hasWord(strings.ToLower(item.Vocab+" "+item.Name), word)
 			}
 
 			if match {
@@ -124,6 +132,9 @@ func findTarget(words []string, items []Itemer, optional bool) (string, Itemer,
 		if optional {
 			return "", nil, nil
 		}
+		for len(words) > 0 && ignore[words[0]] {
+			words = words[1:]
+		}
 		return "You don't see any " + strings.Join(words, " ") + " here.", nil, nil
 	}
 
@@ -215,7 +226,7 @@ func findTopics(words []string, actor Actor) []*Topic {
 
 		isEnd := true
 		for _, topic := range topics {
-			match, _ := regexp.MatchString("(^| )"+word+"( |$)", topic.Vocab)
+			match := hasWord(topic.Vocab, word)
 
 			if match {
 				isEnd = false
@@ -0,0 +1,88 @@
+package engine
+
+//FindItem - searches item by name everywhere in the world, including hidden items
+func (game *Game) FindItem(name string) Itemer {
+	item, _ := game.findItem(name)
+	return item
+}
+
+//MoveItem - moves item from any place of the world to the new owner:
+//"inventory", name of the room, name of the item, or "" to remove it from the world
+func (game *Game) MoveItem(item Itemer, parentName string) bool {
+	var parent *[]Itemer
+	if parentName == "inventory" {
+		parent = &game.Inventory
+	} else if parentName != "" {
+		if owner, _ := game.findItem(parentName); owner != nil {
+			parent = &owner.Basic().Items
+		} else if room := game.Rooms[parentName]; room != nil {
+			parent = &room.BasicRoom().Items
+		} else {
+			return false
+		}
+	}
+
+	if list, idx := game.holder(item); list != nil {
+		*list = append((*list)[:idx], (*list)[idx+1:]...)
+	}
+
+	if parent != nil {
+		*parent = append(*parent, item)
+	}
+	item.Basic().Location = parentName
+	return true
+}
+
+//findItem returns item and the list which holds it
+func (game *Game) findItem(name string) (Itemer, *[]Itemer) {
+	var (
+		found Itemer
+		owner *[]Itemer
+	)
+	game.walk(func(item Itemer, list *[]Itemer, idx int) bool {
+		if item.Basic().Name == name {
+			found, owner = item, list
+			return true
+		}
+		return false
+	})
+	return found, owner
+}
+
+//holder returns the list which holds the item and its index
+func (game *Game) holder(item Itemer) (*[]Itemer, int) {
+	var (
+		owner *[]Itemer
+		index int
+	)
+	game.walk(func(check Itemer, list *[]Itemer, idx int) bool {
+		if check == item {
+			owner, index = list, idx
+			return true
+		}
+		return false
+	})
+	return owner, index
+}
+
+//walk visits every item of the world until callback returns true
+func (game *Game) walk(callback func(item Itemer, list *[]Itemer, idx int) bool) bool {
+	if walkList(&game.Inventory, callback) {
+		return true
+	}
+	for _, room := range game.Rooms {
+		if walkList(&room.BasicRoom().Items, callback) {
+			return true
+		}
+	}
+	return false
+}
+
+func walkList(items *[]Itemer, callback func(item Itemer, list *[]Itemer, idx int) bool) bool {
+	for idx, item := range *items {
+		if callback(item, items, idx) || walkList(&item.Basic().Items, callback) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,7 +1,6 @@
 package game
 
 import (
-	"math/rand"
 	"storyteller/engine"
 	"strings"
 )
@@ -11,15 +10,24 @@ type SampleGame struct {
 	engine.Game
 }
 
+func init() {
+	engine.RegisterRoom("outerRoom", func(game *engine.Game) engine.Spacer {
+		return &outerRoom{}
+	})
+	engine.RegisterItem("bottle", func(game *engine.Game) engine.Itemer {
+		return &bottle{}
+	})
+	engine.RegisterActor("witch", func(game *engine.Game) engine.Actor {
+		return &witch{game: game}
+	})
+}
+
 ///////////////////////////////CUSTOM ROOM SAMPLE///////////////////////////////
 type outerRoom struct {
 	engine.Room
 }
 
 func (room *outerRoom) LeaveRoom(dir string) (bool, string) {
-	if dir == "s" {
-		return false, "It's not a good idea to walk that way."
-	}
 	return true, "You entered the darkness...\n"
 }
 
@@ -27,11 +35,16 @@ func (room *outerRoom) OnAction(action *engine.Action) string {
 	if action.Name == "sleep" {
 		return "Zzzz..."
 	}
+	return room.BasicRoom().OnAction(action)
+}
+
+//ambience daemon runs every turn while player is outside
+func ambience(game *engine.Game) string {
 	events := [3]string{
-		"\nYou hear some noizes from the cave.",
-		"\nHot winds are blowing from the desert.",
+		"You hear some noizes from the cave.",
+		"Hot winds are blowing from the desert.",
 		""}
-	return room.BasicRoom().OnAction(action) + events[rand.Intn(len(events))]
+	return events[game.Random(len(events))]
 }
 
 ///////////////////////////////CUSTOM ITEM SAMPLE///////////////////////////////
@@ -52,20 +65,9 @@ func (item *bottle) OnAction(action *engine.Action, target engine.Itemer) (strin
 	return item.Basic().OnAction(action, target)
 }
 
-///////////////////////////////////////////////////////////////////////////////
-type skull struct {
-	engine.Item
-	game *engine.Game
-}
-
-func (item *skull) OnAction(action *engine.Action, target engine.Itemer) (string, string) {
-	if action == engine.TAKE {
-		if len(item.game.Rooms["Cave"].BasicRoom().Items[0].Basic().Items) < 2 {
-			item.game.IsFinished = true
-			return "As you lift the skull, a volley of poisonous arrows is shot from the walls! You try to dodge the arrows, but they take you by surprise!\nYou are dead.", ""
-		}
-	}
-	return item.Basic().OnAction(action, target)
+//State keeps private fields in saved games
+func (item *bottle) State() interface{} {
+	return &item.isEmpty
 }
 
 ///////////////////////////////CUSTOM ACTOR SAMPLE//////////////////////////////
@@ -78,8 +80,9 @@ func (person *witch) OnTopic(topic *engine.Topic, action *engine.Action, item en
 
 	if action.Name == "give" {
 		if topic != nil && topic.Vocab == "gold skull" {
-			person.game.IsFinished = true
-			return "\"Yes! Thank you!\"\nGame Over."
+			person.game.GrantAward("skull")
+			person.game.Finish("witch")
+			return "\"Yes! Thank you!\""
 		}
 	}
 
@@ -90,7 +93,7 @@ func (person *witch) OnTopic(topic *engine.Topic, action *engine.Action, item en
 		if topic != nil && topic.Vocab == "name" {
 			person.NameEx = "Mellisa the witch"
 			person.Name = "Melissa"
-		} else if topic != nil && strings.Contains(topic.Vocab, "box") {
+		} else if topic != nil && strings.Contains(topic.Vocab, "box") && !topic.IsUsed {
 			item := engine.Item{
 				Name:       "key",
 				Desc:       "A small key that should help to unlock something.",
@@ -113,12 +116,44 @@ func Sample() *SampleGame {
 	context.Game.Actions = []engine.Action{
 		{Name: "sleep"},
 		{Name: "drink", IsItemRequired: true}}
+	context.Game.AddVerb("drink from [item]", &context.Game.Actions[1])
+	context.Game.AddVerb("lie down", &context.Game.Actions[0])
+
+	///////////////////////////////RULE SAMPLE///////////////////////////////
+	context.Game.Rules = []engine.Rule{
+		{
+			Phase:  engine.Instead,
+			Action: "take",
+			Object: "gold skull",
+			When:   []engine.Condition{{Item: "pedestal", Fewer: 2}},
+			Then: []engine.Effect{{
+				Print:  "As you lift the skull, a volley of poisonous arrows is shot from the walls! You try to dodge the arrows, but they take you by surprise!",
+				Item:   "gold skull",
+				Remove: true,
+				Finish: "death"}}},
+		{
+			Phase:  engine.After,
+			Action: "unlock",
+			Object: "box",
+			When:   []engine.Condition{{Item: "box", Flag: "locked", Not: true}},
+			Then:   []engine.Effect{{Award: "box"}}}}
+
+	///////////////////////////////SCORE SAMPLE///////////////////////////////
+	context.Game.Awards = []engine.Award{
+		{Name: "box", Points: 5, Desc: "unlocking the box"},
+		{Name: "skull", Points: 10, Desc: "giving the skull to the witch"}}
+
+	///////////////////////////////ENDING SAMPLE///////////////////////////////
+	context.Game.Endings = []engine.Ending{
+		{ID: "death", Kind: engine.Lose, Text: "You are dead."},
+		{ID: "witch", Kind: engine.Win, Text: "Melissa holds the skull up to the sun and laughs. Whatever she needed it for, the desert will never be the same."}}
+	context.Game.Amusing = "Have you tried to sleep outside the cave? Or to drink from the bottle twice? Or to give the witch some water?"
 
 	context.Game.Rooms["Outside cave"] = &outerRoom{
 		engine.Room{
-			Desc:  "[[img=https://i.imgur.com/ar18tWi.jpg]]You're standing in the bright sunlight just outside of a large, dark, foreboding cave, which lies to the north. Desert lies to the south.",
-			North: "Cave",
-			South: "Desert",
+			Desc:    "[[img=https://i.imgur.com/ar18tWi.jpg]]You're standing in the bright sunlight just outside of a large, dark, foreboding cave, which lies to the north. Desert lies to the south.",
+			Exits:   map[string]string{"north": "Cave", "in": "Cave"},
+			Blocked: map[string]string{"south": "It's not a good idea to walk that way."},
 			Items: []engine.Itemer{
 				&witch{
 					game: &context.Game,
@@ -153,18 +188,32 @@ func Sample() *SampleGame {
 							Name:      "mysterious woman",
 							Vocab:     "girl woman witch melissa",
 							Desc:      "[[img=https://www.elliottsfancydress.co.uk/media/catalog/product/cache/1/image/363x/040ec09b1e35df139433887a97daa66f/w/i/witch_1.jpg]]You see a mysterious woman in dark clothes.\n\"Hey, can we talk? I need your help!\", she asks.",
-							IsVisible: true},
-						NameEx: "Mysterious beautiful woman"}},
+							IsVisible: true,
+							Location:  "Outside cave"},
+						NameEx: "Mysterious beautiful woman",
+						Gender: "female"}},
 				&engine.Item{
 					Name:         "cave",
 					Vocab:        "dark large foreboding",
 					Desc:         "It's a very dark cave.",
 					IsVisible:    true,
-					IsDecoration: true}}}}
+					IsDecoration: true,
+					Location:     "Outside cave"},
+				&engine.Item{
+					Name:          "lantern",
+					AName:         "an old",
+					Vocab:         "lamp old",
+					Desc:          "An old oil lantern, it still has some oil.",
+					IsPickable:    true,
+					IsVisible:     true,
+					IsLightSource: true,
+					Location:      "Outside cave"}}}}
 
 	context.Game.Rooms["Cave"] = &engine.Room{
-		Desc:  "You're inside a dark and musty cave. Sunlight pours in from a passage to the south.",
-		South: "Outside cave",
+		Desc:     "You're inside a dark and musty cave. Sunlight pours in from a passage to the south.",
+		Exits:    map[string]string{"south": "Outside cave", "out": "Outside cave"},
+		IsDark:   true,
+		DarkDesc: "It is pitch dark. Only a faint glimmer of sunlight comes from a passage to the south.",
 		Items: []engine.Itemer{
 			&engine.Item{
 				Name:      "pedestal",
@@ -173,12 +222,10 @@ func Sample() *SampleGame {
 				IsVisible: true,
 				Location:  "Cave",
 				Items: []engine.Itemer{
-					&skull{
-						game: &context.Game,
-						Item: engine.Item{
-							Name:       "gold skull",
-							IsPickable: true,
-							Location:   "pedestal"}}}},
+					&engine.Item{
+						Name:       "gold skull",
+						IsPickable: true,
+						Location:   "pedestal"}}},
 			//-------------------------------------//
 			&engine.Item{
 				Name:        "box",
@@ -204,6 +251,7 @@ func Sample() *SampleGame {
 						Location:   "box"}}}}}
 
 	context.Game.Location = "Outside cave"
+	context.Game.AddDaemon("ambience", "Outside cave", ambience)
 
 	//var _ engine.Itemer = &engine.Person{}
 	return context
@@ -0,0 +1,31 @@
+package game_test
+
+import (
+	"storyteller/engine"
+	"storyteller/game"
+	"storyteller/storyloader"
+	"storyteller/walkthrough"
+	"testing"
+)
+
+func TestSampleWalkthrough(t *testing.T) {
+	newGame := func() engine.Adventurer {
+		return game.Sample()
+	}
+	if err := walkthrough.RunFile("sample.walk", newGame); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSampleStoryWalkthrough(t *testing.T) {
+	newGame := func() engine.Adventurer {
+		story, err := storyloader.LoadFile("sample.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return story
+	}
+	if err := walkthrough.RunFile("sample.json.walk", newGame); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,190 @@
+package script
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenOp
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	number float64
+	line   int
+	column int
+}
+
+var keywords = map[string]bool{
+	"if":     true,
+	"else":   true,
+	"while":  true,
+	"return": true,
+	"true":   true,
+	"false":  true,
+	"nil":    true,
+	"and":    true,
+	"or":     true,
+	"not":    true}
+
+//two characters operators go first
+var operators = []string{
+	"==", "!=", "<=", ">=", "&&", "||",
+	"=", "<", ">", "+", "-", "*", "/", "%", "!", "(", ")", "{", "}", ",", ";"}
+
+type lexer struct {
+	src    []rune
+	pos    int
+	line   int
+	column int
+}
+
+func tokenize(src string) ([]token, error) {
+	lex := &lexer{src: []rune(src), line: 1, column: 1}
+	tokens := []token{}
+
+	for {
+		tok, err := lex.next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, tok)
+		if tok.kind == tokenEOF {
+			return tokens, nil
+		}
+	}
+}
+
+func (lex *lexer) peek() rune {
+	if lex.pos < len(lex.src) {
+		return lex.src[lex.pos]
+	}
+	return 0
+}
+
+func (lex *lexer) advance() rune {
+	r := lex.src[lex.pos]
+	lex.pos++
+	if r == '\n' {
+		lex.line++
+		lex.column = 1
+	} else {
+		lex.column++
+	}
+	return r
+}
+
+func (lex *lexer) errorf(format string, args ...interface{}) error {
+	return &Error{Line: lex.line, Column: lex.column, Message: fmt.Sprintf(format, args...)}
+}
+
+func (lex *lexer) next() (token, error) {
+	//skip spaces and comments
+	for lex.pos < len(lex.src) {
+		r := lex.peek()
+		if r == '#' {
+			for lex.pos < len(lex.src) && lex.peek() != '\n' {
+				lex.advance()
+			}
+			continue
+		}
+		if !strings.ContainsRune(" \t\r\n", r) {
+			break
+		}
+		lex.advance()
+	}
+
+	tok := token{line: lex.line, column: lex.column}
+	if lex.pos >= len(lex.src) {
+		tok.kind = tokenEOF
+		return tok, nil
+	}
+
+	r := lex.peek()
+	switch {
+	case r == '_' || isLetter(r):
+		start := lex.pos
+		for lex.pos < len(lex.src) && (lex.peek() == '_' || isLetter(lex.peek()) || isDigit(lex.peek())) {
+			lex.advance()
+		}
+		tok.kind = tokenIdent
+		tok.text = string(lex.src[start:lex.pos])
+		return tok, nil
+
+	case isDigit(r):
+		start := lex.pos
+		for lex.pos < len(lex.src) && (isDigit(lex.peek()) || lex.peek() == '.') {
+			lex.advance()
+		}
+		tok.kind = tokenNumber
+		tok.text = string(lex.src[start:lex.pos])
+		number, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return tok, lex.errorf("bad number %s", tok.text)
+		}
+		tok.number = number
+		return tok, nil
+
+	case r == '"' || r == '\'':
+		return lex.readString(tok)
+	}
+
+	rest := string(lex.src[lex.pos:])
+	for _, op := range operators {
+		if strings.HasPrefix(rest, op) {
+			for range op {
+				lex.advance()
+			}
+			tok.kind = tokenOp
+			tok.text = op
+			return tok, nil
+		}
+	}
+
+	return tok, lex.errorf("unexpected character %q", r)
+}
+
+func (lex *lexer) readString(tok token) (token, error) {
+	quote := lex.advance()
+	text := []rune{}
+
+	for {
+		if lex.pos >= len(lex.src) {
+			return tok, lex.errorf("unterminated string")
+		}
+		r := lex.advance()
+		if r == quote {
+			break
+		}
+		if r == '\\' && lex.pos < len(lex.src) {
+			switch r = lex.advance(); r {
+			case 'n':
+				r = '\n'
+			case 't':
+				r = '\t'
+			}
+		}
+		text = append(text, r)
+	}
+
+	tok.kind = tokenString
+	tok.text = string(text)
+	return tok, nil
+}
+
+func isLetter(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
@@ -0,0 +1,366 @@
+package script
+
+import (
+	"fmt"
+)
+
+type at struct {
+	line   int
+	column int
+}
+
+type (
+	ifStmt struct {
+		at
+		cond Expr
+		then []Stmt
+		els  []Stmt
+	}
+	whileStmt struct {
+		at
+		cond Expr
+		body []Stmt
+	}
+	returnStmt struct {
+		at
+		value Expr
+	}
+	assignStmt struct {
+		at
+		name  string
+		value Expr
+	}
+	exprStmt struct {
+		at
+		expr Expr
+	}
+)
+
+type (
+	literal struct {
+		at
+		value Value
+	}
+	variable struct {
+		at
+		name string
+	}
+	call struct {
+		at
+		name string
+		args []Expr
+	}
+	unary struct {
+		at
+		op string
+		x  Expr
+	}
+	binary struct {
+		at
+		op    string
+		left  Expr
+		right Expr
+	}
+)
+
+//Stmt - statement of the script
+type Stmt interface {
+	exec(run *runner) (bool, Value, error)
+}
+
+//Expr - expression of the script
+type Expr interface {
+	eval(run *runner) (Value, error)
+}
+
+//binary operators by priority, lowest first
+var priorities = [][]string{
+	{"||", "or"},
+	{"&&", "and"},
+	{"==", "!=", "<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"}}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func parse(src string) ([]Stmt, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	body := []Stmt{}
+	for p.skipSeparators(); p.current().kind != tokenEOF; p.skipSeparators() {
+		stmt, err := p.statement()
+		if err != nil {
+			return nil, err
+		}
+		body = append(body, stmt)
+	}
+	return body, nil
+}
+
+func (p *parser) current() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+//is checks if current token is an operator or a keyword
+func (p *parser) is(texts ...string) bool {
+	tok := p.current()
+	if tok.kind != tokenOp && tok.kind != tokenIdent {
+		return false
+	}
+	for _, text := range texts {
+		if tok.text == text {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.is(text) {
+		return p.errorf("expected %s", text)
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	tok := p.current()
+	msg := fmt.Sprintf(format, args...)
+	if tok.kind == tokenEOF {
+		msg += ", found end of script"
+	} else {
+		msg += ", found " + tok.text
+	}
+	return &Error{Line: tok.line, Column: tok.column, Message: msg}
+}
+
+func (p *parser) position() at {
+	tok := p.current()
+	return at{tok.line, tok.column}
+}
+
+//skipSeparators - statements may be separated by ";", empty ones are allowed
+func (p *parser) skipSeparators() {
+	for p.is(";") {
+		p.next()
+	}
+}
+
+func (p *parser) statement() (Stmt, error) {
+	pos := p.position()
+
+	switch {
+	case p.is("if"):
+		return p.ifStatement()
+
+	case p.is("while"):
+		p.next()
+		cond, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		body, err := p.block()
+		if err != nil {
+			return nil, err
+		}
+		return &whileStmt{pos, cond, body}, nil
+
+	case p.is("return"):
+		p.next()
+		if p.is("}", ";") || p.current().kind == tokenEOF {
+			return &returnStmt{pos, nil}, nil
+		}
+		value, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		return &returnStmt{pos, value}, nil
+	}
+
+	tok := p.current()
+	if tok.kind == tokenIdent && !keywords[tok.text] && p.tokens[p.pos+1].text == "=" {
+		p.next()
+		p.next()
+		value, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		return &assignStmt{pos, tok.text, value}, nil
+	}
+
+	expr, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	return &exprStmt{pos, expr}, nil
+}
+
+func (p *parser) ifStatement() (Stmt, error) {
+	pos := p.position()
+	p.next()
+
+	cond, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	then, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := &ifStmt{pos, cond, then, nil}
+	if !p.is("else") {
+		return stmt, nil
+	}
+	p.next()
+
+	if p.is("if") {
+		nested, err := p.ifStatement()
+		if err != nil {
+			return nil, err
+		}
+		stmt.els = []Stmt{nested}
+		return stmt, nil
+	}
+
+	stmt.els, err = p.block()
+	return stmt, err
+}
+
+func (p *parser) block() ([]Stmt, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	body := []Stmt{}
+	for p.skipSeparators(); !p.is("}"); p.skipSeparators() {
+		if p.current().kind == tokenEOF {
+			return nil, p.errorf("expected }")
+		}
+		stmt, err := p.statement()
+		if err != nil {
+			return nil, err
+		}
+		body = append(body, stmt)
+	}
+	p.next()
+
+	return body, nil
+}
+
+func (p *parser) expression() (Expr, error) {
+	return p.binary(0)
+}
+
+func (p *parser) binary(priority int) (Expr, error) {
+	if priority == len(priorities) {
+		return p.unary()
+	}
+
+	left, err := p.binary(priority + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for p.is(priorities[priority]...) {
+		pos := p.position()
+		op := p.next().text
+		right, err := p.binary(priority + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &binary{pos, op, left, right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) unary() (Expr, error) {
+	if p.is("!", "not", "-") {
+		pos := p.position()
+		op := p.next().text
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &unary{pos, op, x}, nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (Expr, error) {
+	pos := p.position()
+	tok := p.current()
+
+	switch tok.kind {
+	case tokenNumber:
+		p.next()
+		return &literal{pos, tok.number}, nil
+
+	case tokenString:
+		p.next()
+		return &literal{pos, tok.text}, nil
+
+	case tokenIdent:
+		switch tok.text {
+		case "true", "false":
+			p.next()
+			return &literal{pos, tok.text == "true"}, nil
+		case "nil":
+			p.next()
+			return &literal{pos, nil}, nil
+		}
+		if keywords[tok.text] {
+			return nil, p.errorf("unexpected keyword")
+		}
+		p.next()
+
+		if !p.is("(") {
+			return &variable{pos, tok.text}, nil
+		}
+		p.next()
+
+		args := []Expr{}
+		for !p.is(")") {
+			if len(args) > 0 {
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+			arg, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+		}
+		p.next()
+		return &call{pos, tok.text, args}, nil
+
+	case tokenOp:
+		if tok.text == "(" {
+			p.next()
+			expr, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			return expr, p.expect(")")
+		}
+	}
+
+	return nil, p.errorf("expected expression")
+}
@@ -0,0 +1,379 @@
+//Package script is a tiny sandboxed language for story event handlers.
+//
+//	# comments start with hash
+//	missing = 2 - count("pedestal")
+//	if missing > 0 and not has("lamp") {
+//		say("As you lift the skull, a volley of arrows is shot from the walls!")
+//		end("death")
+//		return true
+//	}
+//
+//Values are nil, booleans, numbers and strings. Scripts can only call
+//functions provided by the host and every run is limited by number of steps and memory for strings.
+//Variables live for one run only, the host keeps the state between runs.
+package script
+
+import (
+	"fmt"
+	"strconv"
+)
+
+//DefaultMaxSteps - steps limit, if Env.MaxSteps is not set
+const DefaultMaxSteps = 10000
+
+//DefaultMaxStringLength - length limit of a string made by the script, if Env.MaxStringLength is not set
+const DefaultMaxStringLength = 10000
+
+//DefaultMaxAllocation - limit of bytes of all strings made by the script, if Env.MaxAllocation is not set
+const DefaultMaxAllocation = 1000000
+
+//Value - nil, bool, float64 or string
+type Value interface{}
+
+//Func - host function available for scripts
+type Func func(args []Value) (Value, error)
+
+//Env - everything script can access
+type Env struct {
+	Vars     map[string]Value
+	Funcs    map[string]Func
+	MaxSteps int
+
+	MaxStringLength int
+	MaxAllocation   int
+}
+
+//Program - compiled script
+type Program struct {
+	body []Stmt
+}
+
+//Error - compilation or runtime error
+type Error struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (err *Error) Error() string {
+	return fmt.Sprintf("line %d:%d: %s", err.Line, err.Column, err.Message)
+}
+
+type runner struct {
+	env       *Env
+	steps     int
+	allocated int
+}
+
+//Compile - parses the script
+func Compile(src string) (*Program, error) {
+	body, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+	return &Program{body: body}, nil
+}
+
+//Run executes the program, returns value of the return statement.
+//Panics of the host functions are returned as errors, so a script can't crash the bot.
+func (program *Program) Run(env *Env) (value Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			value, err = nil, &Error{Message: fmt.Sprint("script failed: ", r)}
+		}
+	}()
+
+	if env.Vars == nil {
+		env.Vars = make(map[string]Value)
+	}
+	run := &runner{env: env}
+	_, value, err = run.block(program.body)
+	return value, err
+}
+
+//IsTrue - nil, false, zero and empty string are false, everything else is true
+func IsTrue(value Value) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	}
+	return true
+}
+
+//String - text representation of the value
+func String(value Value) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+func (run *runner) step(pos at) error {
+	limit := run.env.MaxSteps
+	if limit <= 0 {
+		limit = DefaultMaxSteps
+	}
+	run.steps++
+	if run.steps > limit {
+		return pos.errorf("script is too long, more than %d steps", limit)
+	}
+	return nil
+}
+
+//alloc - counts bytes of a new string, so the script can't eat the memory
+func (run *runner) alloc(pos at, size int) error {
+	maxLength := run.env.MaxStringLength
+	if maxLength <= 0 {
+		maxLength = DefaultMaxStringLength
+	}
+	if size > maxLength {
+		return pos.errorf("string is too long, more than %d bytes", maxLength)
+	}
+
+	limit := run.env.MaxAllocation
+	if limit <= 0 {
+		limit = DefaultMaxAllocation
+	}
+	run.allocated += size
+	if run.allocated > limit {
+		return pos.errorf("script uses too much memory, more than %d bytes", limit)
+	}
+	return nil
+}
+
+func (pos at) errorf(format string, args ...interface{}) error {
+	return &Error{Line: pos.line, Column: pos.column, Message: fmt.Sprintf(format, args...)}
+}
+
+//block returns true, if return statement was executed
+func (run *runner) block(body []Stmt) (bool, Value, error) {
+	for _, stmt := range body {
+		done, value, err := stmt.exec(run)
+		if err != nil || done {
+			return done, value, err
+		}
+	}
+	return false, nil, nil
+}
+
+func (stmt *ifStmt) exec(run *runner) (bool, Value, error) {
+	if err := run.step(stmt.at); err != nil {
+		return false, nil, err
+	}
+	cond, err := stmt.cond.eval(run)
+	if err != nil {
+		return false, nil, err
+	}
+	if IsTrue(cond) {
+		return run.block(stmt.then)
+	}
+	return run.block(stmt.els)
+}
+
+func (stmt *whileStmt) exec(run *runner) (bool, Value, error) {
+	for {
+		if err := run.step(stmt.at); err != nil {
+			return false, nil, err
+		}
+		cond, err := stmt.cond.eval(run)
+		if err != nil || !IsTrue(cond) {
+			return false, nil, err
+		}
+		done, value, err := run.block(stmt.body)
+		if err != nil || done {
+			return done, value, err
+		}
+	}
+}
+
+func (stmt *returnStmt) exec(run *runner) (bool, Value, error) {
+	if err := run.step(stmt.at); err != nil {
+		return false, nil, err
+	}
+	if stmt.value == nil {
+		return true, nil, nil
+	}
+	value, err := stmt.value.eval(run)
+	return true, value, err
+}
+
+func (stmt *assignStmt) exec(run *runner) (bool, Value, error) {
+	if err := run.step(stmt.at); err != nil {
+		return false, nil, err
+	}
+	value, err := stmt.value.eval(run)
+	if err == nil {
+		run.env.Vars[stmt.name] = value
+	}
+	return false, nil, err
+}
+
+func (stmt *exprStmt) exec(run *runner) (bool, Value, error) {
+	if err := run.step(stmt.at); err != nil {
+		return false, nil, err
+	}
+	_, err := stmt.expr.eval(run)
+	return false, nil, err
+}
+
+func (expr *literal) eval(run *runner) (Value, error) {
+	return expr.value, nil
+}
+
+func (expr *variable) eval(run *runner) (Value, error) {
+	value, ok := run.env.Vars[expr.name]
+	if !ok {
+		return nil, expr.errorf("unknown variable %s", expr.name)
+	}
+	return value, nil
+}
+
+func (expr *call) eval(run *runner) (Value, error) {
+	if err := run.step(expr.at); err != nil {
+		return nil, err
+	}
+
+	fn, ok := run.env.Funcs[expr.name]
+	if !ok {
+		return nil, expr.errorf("unknown function %s", expr.name)
+	}
+
+	args := []Value{}
+	for _, arg := range expr.args {
+		value, err := arg.eval(run)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, value)
+	}
+
+	value, err := fn(args)
+	if err != nil {
+		return nil, expr.errorf("%s: %v", expr.name, err)
+	}
+	return value, nil
+}
+
+func (expr *unary) eval(run *runner) (Value, error) {
+	x, err := expr.x.eval(run)
+	if err != nil {
+		return nil, err
+	}
+
+	if expr.op == "-" {
+		number, ok := x.(float64)
+		if !ok {
+			return nil, expr.errorf("can't negate %s", String(x))
+		}
+		return -number, nil
+	}
+	return !IsTrue(x), nil
+}
+
+func (expr *binary) eval(run *runner) (Value, error) {
+	if err := run.step(expr.at); err != nil {
+		return nil, err
+	}
+
+	left, err := expr.left.eval(run)
+	if err != nil {
+		return nil, err
+	}
+
+	//short circuit
+	switch expr.op {
+	case "&&", "and":
+		if !IsTrue(left) {
+			return false, nil
+		}
+		right, err := expr.right.eval(run)
+		return IsTrue(right), err
+	case "||", "or":
+		if IsTrue(left) {
+			return true, nil
+		}
+		right, err := expr.right.eval(run)
+		return IsTrue(right), err
+	}
+
+	right, err := expr.right.eval(run)
+	if err != nil {
+		return nil, err
+	}
+
+	switch expr.op {
+	case "==":
+		return left == right, nil
+	case "!=":
+		return left != right, nil
+	}
+
+	a, isNumber := left.(float64)
+	b, isNumber2 := right.(float64)
+
+	if !isNumber || !isNumber2 {
+		if expr.op == "+" {
+			x, y := String(left), String(right)
+			if err := run.alloc(expr.at, len(x)+len(y)); err != nil {
+				return nil, err
+			}
+			return x + y, nil
+		}
+
+		x, isString := left.(string)
+		y, isString2 := right.(string)
+		if !isString || !isString2 {
+			return nil, expr.errorf("can't apply %s to %s and %s", expr.op, String(left), String(right))
+		}
+
+		switch expr.op {
+		case "<":
+			return x < y, nil
+		case "<=":
+			return x <= y, nil
+		case ">":
+			return x > y, nil
+		case ">=":
+			return x >= y, nil
+		}
+		return nil, expr.errorf("can't apply %s to strings", expr.op)
+	}
+
+	switch expr.op {
+	case "+":
+		return a + b, nil
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "/":
+		if b == 0 {
+			return nil, expr.errorf("division by zero")
+		}
+		return a / b, nil
+	case "%":
+		if int64(b) == 0 {
+			return nil, expr.errorf("division by zero")
+		}
+		return float64(int64(a) % int64(b)), nil
+	case "<":
+		return a < b, nil
+	case "<=":
+		return a <= b, nil
+	case ">":
+		return a > b, nil
+	case ">=":
+		return a >= b, nil
+	}
+
+	return nil, expr.errorf("unknown operator %s", expr.op)
+}
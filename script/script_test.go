@@ -0,0 +1,72 @@
+package script
+
+import (
+	"strings"
+	"testing"
+)
+
+func run(t *testing.T, src string, env *Env) (Value, error) {
+	program, err := Compile(src)
+	if err != nil {
+		t.Fatalf("%s: %v", src, err)
+	}
+	return program.Run(env)
+}
+
+func TestRun(t *testing.T) {
+	said := []string{}
+	env := &Env{
+		Vars: map[string]Value{"count": float64(3)},
+		Funcs: map[string]Func{"say": func(args []Value) (Value, error) {
+			said = append(said, String(args[0]))
+			return nil, nil
+		}}}
+	src := `
+		# counts down
+		left = count
+		while left > 0 {
+			say("left " + left)
+			left = left - 1
+		}
+		return count % 2 == 1 and not left`
+
+	value, err := run(t, src, env)
+	if err != nil || value != true {
+		t.Fatalf("value %v, error %v", value, err)
+	}
+	if got := strings.Join(said, ", "); got != "left 3, left 2, left 1" {
+		t.Fatalf("said %q", got)
+	}
+}
+
+func TestStepLimit(t *testing.T) {
+	_, err := run(t, "while true { }", &Env{MaxSteps: 100})
+	if err == nil || !strings.Contains(err.Error(), "more than 100 steps") {
+		t.Fatalf("error %v", err)
+	}
+}
+
+func TestDivisionByZero(t *testing.T) {
+	for _, src := range []string{"return 1 / 0", "return 5 % 0", "return 5 % 0.5"} {
+		if _, err := run(t, src, &Env{}); err == nil || !strings.Contains(err.Error(), "division by zero") {
+			t.Errorf("%s: error %v", src, err)
+		}
+	}
+}
+
+func TestStringLimit(t *testing.T) {
+	_, err := run(t, `s = "ab"
+		while true { s = s + s }`, &Env{MaxStringLength: 1000})
+	if err == nil || !strings.Contains(err.Error(), "string is too long") {
+		t.Fatalf("error %v", err)
+	}
+}
+
+func TestHostPanic(t *testing.T) {
+	env := &Env{Funcs: map[string]Func{"crash": func(args []Value) (Value, error) {
+		panic("boom")
+	}}}
+	if _, err := run(t, "crash()", env); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("error %v", err)
+	}
+}
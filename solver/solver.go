@@ -0,0 +1,370 @@
+//Package solver explores the story by breadth-first search over game states to find the shortest ways
+//to every ending and the parts of the world which can't be reached. Commands are made up from the exits
+//of the current room, the items in scope, the actions and the actor topics, so puzzles which need
+//an unusual command (like a password) stay unsolved.
+package solver
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"sort"
+	"storyteller/engine"
+	"strings"
+)
+
+//Options - search limits
+type Options struct {
+	MaxDepth  int   //commands in a path
+	MaxStates int   //distinct world states to explore
+	Seed      int64 //seed of the game random source
+}
+
+//Solution - the shortest command sequence found for the ending
+type Solution struct {
+	Ending   engine.Ending
+	Commands []string
+}
+
+//Report - result of the search
+type Report struct {
+	Solutions      []Solution //sorted by ending kind (wins first) and ID
+	UnvisitedRooms []string
+	UnseenItems    []string
+	States         int
+	IsComplete     bool //false if the search stopped at the limits
+}
+
+//IsWinnable - true if any winning ending was reached
+func (report *Report) IsWinnable() bool {
+	for _, solution := range report.Solutions {
+		if solution.Ending.Kind == engine.Win {
+			return true
+		}
+	}
+	return false
+}
+
+type node struct {
+	state []byte
+	path  []string
+}
+
+type solver struct {
+	game    engine.Adventurer
+	options Options
+	visited map[[sha256.Size]byte]bool
+	rooms   map[string]bool
+	items   map[string]bool
+	endings map[string]*Solution
+}
+
+//Solve - explores the fresh game instance, states are switched with Save and Load like undo does.
+//States are keyed by the hash of the save of the normalized world, see normalize.
+func Solve(game engine.Adventurer, options Options) (*Report, error) {
+	s := &solver{
+		game:    game,
+		options: options,
+		visited: make(map[[sha256.Size]byte]bool),
+		rooms:   make(map[string]bool),
+		items:   make(map[string]bool),
+		endings: make(map[string]*Solution)}
+
+	game.BasicGame().SetSeed(options.Seed)
+	game.BasicGame().UndoDepth = -1
+	game.Intro()
+
+	allRooms, allItems := world(game.BasicGame())
+
+	state, err := s.save()
+	if err != nil {
+		return nil, err
+	}
+	s.visited[sha256.Sum256(state)] = true
+
+	report := &Report{IsComplete: true}
+	queue := []node{{state, nil}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if err := s.load(current.state); err != nil {
+			return nil, err
+		}
+		s.look(game)
+
+		if game.BasicGame().IsFinished() {
+			s.finish(game.BasicGame().Ending, current.path)
+			continue
+		}
+		if len(current.path) >= options.MaxDepth {
+			report.IsComplete = false
+			continue
+		}
+
+		isChanged := false
+		for _, command := range commands(game) {
+			//commands which changed nothing don't need the state to be loaded again
+			if isChanged {
+				if err := s.load(current.state); err != nil {
+					return nil, err
+				}
+			}
+			msg := engine.Process(game, command)
+
+			state, err := s.save()
+			if err != nil {
+				return nil, err
+			}
+			//a question waits for the reply, it mustn't take the next command
+			isChanged = !bytes.Equal(state, current.state) || strings.HasSuffix(msg, "?")
+			key := sha256.Sum256(state)
+			if s.visited[key] {
+				continue
+			}
+			if len(s.visited) >= options.MaxStates {
+				report.IsComplete = false
+				queue = nil
+				break
+			}
+			s.visited[key] = true
+
+			path := append(append([]string{}, current.path...), command)
+			queue = append(queue, node{state, path})
+		}
+	}
+
+	report.States = len(s.visited)
+	for _, solution := range s.endings {
+		report.Solutions = append(report.Solutions, *solution)
+	}
+	sort.Slice(report.Solutions, func(i, j int) bool {
+		a, b := report.Solutions[i].Ending, report.Solutions[j].Ending
+		if a.Kind != b.Kind {
+			return a.Kind > b.Kind
+		}
+		return a.ID < b.ID
+	})
+
+	report.UnvisitedRooms = missing(allRooms, s.rooms)
+	report.UnseenItems = missing(allItems, s.items)
+	return report, nil
+}
+
+func (s *solver) load(state []byte) error {
+	return s.game.BasicGame().Load(bytes.NewReader(state))
+}
+
+//save - the state of the normalized world
+func (s *solver) save() ([]byte, error) {
+	s.normalize()
+	var buf bytes.Buffer
+	if err := s.game.BasicGame().Save(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+//normalize - the same world gives the same save: turns and the random sequence are reset,
+//items are sorted by name, so taking the lantern before the sword is the same as after
+func (s *solver) normalize() {
+	game := s.game.BasicGame()
+	game.Turns = 0
+	game.SetSeed(s.options.Seed)
+	sortItems(game.Inventory)
+	for _, room := range game.Rooms {
+		sortItems(room.BasicRoom().Items)
+	}
+}
+
+func sortItems(items []engine.Itemer) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Basic().Name < items[j].Basic().Name
+	})
+	for _, item := range items {
+		sortItems(item.Basic().Items)
+	}
+}
+
+//look - notes the room and the items the player can see
+func (s *solver) look(game engine.Adventurer) {
+	s.rooms[game.BasicGame().Location] = true
+	for _, item := range scope(game.BasicGame()) {
+		s.items[item.Basic().Name] = true
+	}
+}
+
+func (s *solver) finish(ending *engine.Ending, path []string) {
+	if solution := s.endings[ending.ID]; solution == nil || len(path) < len(solution.Commands) {
+		s.endings[ending.ID] = &Solution{*ending, path}
+	}
+}
+
+//scope - visible items the player can refer to
+func scope(game *engine.Game) []engine.Itemer {
+	items := visible(game.Inventory)
+	if game.IsLit() {
+		items = append(items, visible(game.CurrentRoom().BasicRoom().Items)...)
+	}
+	return items
+}
+
+func visible(items []engine.Itemer) []engine.Itemer {
+	result := []engine.Itemer{}
+	for _, i := range items {
+		item := i.Basic()
+		if item.IsVisible && !item.IsDisabled {
+			result = append(result, i)
+			result = append(result, visible(item.Items)...)
+		}
+	}
+	return result
+}
+
+//commands - candidate commands for the current state
+func commands(game engine.Adventurer) []string {
+	base := game.BasicGame()
+	room := game.CurrentRoom().BasicRoom()
+	result := []string{}
+
+	exits := []string{}
+	for name := range room.Exits {
+		exits = append(exits, name)
+	}
+	sort.Strings(exits)
+	result = append(result, exits...)
+
+	items := scope(base)
+	actors := []engine.Actor{}
+	for _, i := range items {
+		if actor, ok := i.(engine.Actor); ok {
+			actors = append(actors, actor)
+		}
+	}
+
+	for _, i := range items {
+		item := i.Basic()
+		name := strings.ToLower(item.Name)
+		result = append(result, "examine "+name)
+
+		if _, ok := i.(engine.Actor); ok {
+			continue
+		}
+		if item.IsPickable && item.Location != "inventory" {
+			result = append(result, "take "+name)
+		}
+		if item.IsContainer || item.IsOpen {
+			result = append(result, "open "+name, "close "+name)
+		}
+		if _, ok := i.(engine.Doorer); ok {
+			result = append(result, "open "+name, "close "+name)
+		}
+		if item.IsLightSource {
+			result = append(result, "light "+name, "extinguish "+name)
+		}
+		result = append(result, "use "+name)
+
+		if item.Location != "inventory" {
+			continue
+		}
+		result = append(result, "drop "+name)
+		for _, t := range items {
+			target := t.Basic()
+			if t == i {
+				continue
+			}
+			targetName := strings.ToLower(target.Name)
+			if target.IsSurface {
+				result = append(result, "put "+name+" on "+targetName)
+			}
+			if target.IsContainer {
+				result = append(result, "put "+name+" in "+targetName)
+			}
+			if _, ok := t.(engine.Doorer); ok || target.IsContainer {
+				result = append(result, "unlock "+targetName+" with "+name, "lock "+targetName+" with "+name)
+			}
+			if target.IsUseTarget {
+				result = append(result, "use "+name+" on "+targetName)
+			}
+		}
+		for _, actor := range actors {
+			result = append(result, "give "+name+" to "+strings.ToLower(actor.Basic().Name))
+		}
+	}
+
+	for _, actor := range actors {
+		name := strings.ToLower(actor.Basic().Name)
+		for _, topic := range actor.BasicPerson().Topics {
+			words := strings.Fields(strings.ToLower(topic.Vocab))
+			if strings.Contains(topic.Action, "ask") && len(words) > 0 {
+				result = append(result, "ask "+name+" about "+words[0])
+			}
+		}
+	}
+
+	for _, action := range base.Actions {
+		if action.IsPredefined {
+			continue
+		}
+		switch {
+		case action.IsItemRequired:
+			for _, i := range items {
+				result = append(result, action.Name+" "+strings.ToLower(i.Basic().Name))
+			}
+		case action.IsActorRequired:
+			for _, actor := range actors {
+				result = append(result, action.Name+" "+strings.ToLower(actor.Basic().Name))
+			}
+		default:
+			result = append(result, action.Name)
+		}
+	}
+
+	return unique(result)
+}
+
+func unique(list []string) []string {
+	seen := make(map[string]bool)
+	result := []string{}
+	for _, s := range list {
+		if !seen[s] {
+			seen[s] = true
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+//world - names of all rooms and items at the start
+func world(game *engine.Game) ([]string, []string) {
+	rooms := []string{}
+	for name := range game.Rooms {
+		rooms = append(rooms, name)
+	}
+
+	items := names(game.Inventory)
+	for _, room := range game.Rooms {
+		items = append(items, names(room.BasicRoom().Items)...)
+	}
+	return rooms, items
+}
+
+func names(items []engine.Itemer) []string {
+	result := []string{}
+	for _, item := range items {
+		result = append(result, item.Basic().Name)
+		result = append(result, names(item.Basic().Items)...)
+	}
+	return result
+}
+
+//missing - sorted names which weren't met
+func missing(all []string, met map[string]bool) []string {
+	result := []string{}
+	for _, name := range unique(all) {
+		if !met[name] {
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,267 @@
+package storyloader
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"storyteller/engine"
+	"storyteller/script"
+	"strings"
+)
+
+//api - functions available for scripts, it's the only way for scripts to change the game
+type api struct {
+	game    *engine.Game
+	output  []string
+	isProbe bool //the script only checks something, functions with effects do nothing
+}
+
+func newAPI(game *engine.Game) *api {
+	return &api{game: game}
+}
+
+//effects - functions which change the game or the random sequence, they do nothing while probing
+var effects = map[string]bool{
+	"say": true, "move": true, "setflag": true, "rename": true, "describe": true, "random": true, "award": true, "end": true}
+
+func (api *api) funcs() map[string]script.Func {
+	funcs := api.allFuncs()
+	if api.isProbe {
+		for name := range effects {
+			funcs[name] = probe
+		}
+	}
+	return funcs
+}
+
+func probe(args []script.Value) (script.Value, error) {
+	return nil, nil
+}
+
+func (api *api) allFuncs() map[string]script.Func {
+	return map[string]script.Func{
+		"say":      api.say,
+		"here":     api.here,
+		"location": api.location,
+		"has":      api.has,
+		"count":    api.count,
+		"visited":  api.visited,
+		"move":     api.move,
+		"flag":     api.flag,
+		"setflag":  api.setFlag,
+		"rename":   api.rename,
+		"describe": api.describe,
+		"random":   api.random,
+		"award":    api.award,
+		"end":      api.end}
+}
+
+func (api *api) item(args []script.Value, idx int) (*engine.Item, error) {
+	name, err := stringArg(args, idx)
+	if err != nil {
+		return nil, err
+	}
+	item := api.game.FindItem(name)
+	if item == nil {
+		return nil, fmt.Errorf("unknown item %q", name)
+	}
+	return item.Basic(), nil
+}
+
+func numberArg(args []script.Value, idx int) (float64, error) {
+	if idx >= len(args) {
+		return 0, fmt.Errorf("argument %d is missing", idx+1)
+	}
+	number, ok := args[idx].(float64)
+	if !ok {
+		return 0, fmt.Errorf("argument %d should be a number", idx+1)
+	}
+	return number, nil
+}
+
+func stringArg(args []script.Value, idx int) (string, error) {
+	if idx >= len(args) {
+		return "", fmt.Errorf("argument %d is missing", idx+1)
+	}
+	text, ok := args[idx].(string)
+	if !ok {
+		return "", fmt.Errorf("argument %d should be a string", idx+1)
+	}
+	return text, nil
+}
+
+//say(text, ...) - prints text
+func (api *api) say(args []script.Value) (script.Value, error) {
+	text := []string{}
+	for _, arg := range args {
+		text = append(text, script.String(arg))
+	}
+	api.output = append(api.output, strings.Join(text, ""))
+	return nil, nil
+}
+
+//here() - name of the current room
+func (api *api) here(args []script.Value) (script.Value, error) {
+	return api.game.Location, nil
+}
+
+//location(item) - name of the item owner: room, item or "inventory"
+func (api *api) location(args []script.Value) (script.Value, error) {
+	item, err := api.item(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	return item.Location, nil
+}
+
+//has(item) - checks if player holds the item
+func (api *api) has(args []script.Value) (script.Value, error) {
+	name, err := stringArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	item := api.game.FindItem(name)
+	return item != nil && item.Basic().Location == "inventory", nil
+}
+
+//count(item) - number of items inside the item
+func (api *api) count(args []script.Value) (script.Value, error) {
+	item, err := api.item(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	return float64(len(item.Items)), nil
+}
+
+//visited(room) - checks if room was visited
+func (api *api) visited(args []script.Value) (script.Value, error) {
+	name, err := stringArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	room := api.game.Rooms[name]
+	if room == nil {
+		return nil, fmt.Errorf("unknown room %q", name)
+	}
+	return room.BasicRoom().IsVisited, nil
+}
+
+//move(item, owner) - moves item to room, other item or "inventory", "" removes it from the game
+func (api *api) move(args []script.Value) (script.Value, error) {
+	name, err := stringArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	owner, err := stringArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	item := api.game.FindItem(name)
+	if item == nil {
+		return nil, fmt.Errorf("unknown item %q", name)
+	}
+	if !api.game.MoveItem(item, owner) {
+		return nil, fmt.Errorf("unknown owner %q", owner)
+	}
+	return nil, nil
+}
+
+//flag(item, name) - item flag: open, locked, visible, disabled, pickable, lit, etc.
+func (api *api) flag(args []script.Value) (script.Value, error) {
+	flag, err := api.flagArg(args)
+	if err != nil {
+		return nil, err
+	}
+	return *flag, nil
+}
+
+//setflag(item, name, value) - changes item flag
+func (api *api) setFlag(args []script.Value) (script.Value, error) {
+	flag, err := api.flagArg(args)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) < 3 {
+		return nil, errors.New("argument 3 is missing")
+	}
+	*flag = script.IsTrue(args[2])
+	return nil, nil
+}
+
+func (api *api) flagArg(args []script.Value) (*bool, error) {
+	item, err := api.item(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	name, err := stringArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	flag := item.Flag(name)
+	if flag == nil {
+		return nil, fmt.Errorf("unknown flag %q", name)
+	}
+	return flag, nil
+}
+
+//rename(item, name) - changes item name
+func (api *api) rename(args []script.Value) (script.Value, error) {
+	item, err := api.item(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	text, err := stringArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	item.Name = text
+	return nil, nil
+}
+
+//describe(item, text) - changes item description
+func (api *api) describe(args []script.Value) (script.Value, error) {
+	item, err := api.item(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	text, err := stringArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	item.Desc = text
+	return nil, nil
+}
+
+//random(n) - random number from 0 to n-1, n is up to math.MaxInt32, so it fits int everywhere
+func (api *api) random(args []script.Value) (script.Value, error) {
+	n, err := numberArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	if !(n >= 1 && n <= math.MaxInt32) {
+		return nil, fmt.Errorf("argument 1 should be from 1 to %d", math.MaxInt32)
+	}
+	return float64(api.game.Random(int(n))), nil
+}
+
+//award(name) - grants award once, returns false if it was already granted
+func (api *api) award(args []script.Value) (script.Value, error) {
+	name, err := stringArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	return api.game.GrantAward(name), nil
+}
+
+//end(ending) - finishes the game with the ending from the story
+func (api *api) end(args []script.Value) (script.Value, error) {
+	id, err := stringArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	if !api.game.Finish(id) {
+		return nil, fmt.Errorf("unknown ending %q", id)
+	}
+	return nil, nil
+}
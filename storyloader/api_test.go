@@ -0,0 +1,19 @@
+package storyloader
+
+import (
+	"storyteller/engine"
+	"storyteller/script"
+	"testing"
+)
+
+func TestRandomArgument(t *testing.T) {
+	api := newAPI(&engine.Game{})
+	for _, n := range []float64{0, -1, 1e300, -1e300} {
+		if _, err := api.random([]script.Value{n}); err == nil {
+			t.Errorf("random(%g) should fail", n)
+		}
+	}
+	if value, err := api.random([]script.Value{float64(3)}); err != nil || value.(float64) < 0 || value.(float64) > 2 {
+		t.Errorf("random(3) = %v, %v", value, err)
+	}
+}
@@ -0,0 +1,117 @@
+package storyloader
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+//Error - problem found in a story file
+type Error struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (err *Error) Error() string {
+	if err.Line == 0 {
+		return err.File + ": " + err.Message
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", err.File, err.Line, err.Column, err.Message)
+}
+
+//ErrorList - all problems found in a story file
+type ErrorList []*Error
+
+func (list ErrorList) Error() string {
+	msg := []string{}
+	for _, err := range list {
+		msg = append(msg, err.Error())
+	}
+	return strings.Join(msg, "\n")
+}
+
+//source keeps positions of every value in the file by its path, like rooms[0].exits.north
+type source struct {
+	file      string
+	data      []byte
+	positions map[string]int64
+	errors    ErrorList
+}
+
+func newSource(file string, data []byte) *source {
+	src := &source{file: file, data: data, positions: make(map[string]int64)}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	src.index(decoder, "")
+	return src
+}
+
+func (src *source) index(decoder *json.Decoder, path string) {
+	src.positions[path] = src.skipSpaces(decoder.InputOffset())
+
+	token, err := decoder.Token()
+	if err != nil {
+		return
+	}
+
+	switch token {
+	case json.Delim('{'):
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return
+			}
+			src.index(decoder, join(path, fmt.Sprint(key)))
+		}
+		decoder.Token()
+	case json.Delim('['):
+		for i := 0; decoder.More(); i++ {
+			src.index(decoder, fmt.Sprintf("%s[%d]", path, i))
+		}
+		decoder.Token()
+	}
+}
+
+func (src *source) skipSpaces(offset int64) int64 {
+	for offset < int64(len(src.data)) && strings.IndexByte(" \t\r\n:,", src.data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+//errorf reports a problem at the value with the given path
+func (src *source) errorf(path string, format string, args ...interface{}) {
+	offset, ok := src.positions[path]
+	if !ok {
+		offset = -1
+	}
+	src.errorAt(offset, fmt.Sprintf(format, args...))
+}
+
+func (src *source) errorAt(offset int64, msg string) {
+	err := &Error{File: src.file, Message: msg}
+	if offset >= 0 {
+		err.Line, err.Column = src.position(offset)
+	}
+	src.errors = append(src.errors, err)
+}
+
+func (src *source) position(offset int64) (int, int) {
+	if offset > int64(len(src.data)) {
+		offset = int64(len(src.data))
+	}
+	before := src.data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := int(offset) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
+
+func join(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,590 @@
+package storyloader
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"storyteller/engine"
+	"storyteller/script"
+	"strings"
+)
+
+var phases = map[string]engine.Phase{
+	"before":  engine.Before,
+	"instead": engine.Instead,
+	"after":   engine.After}
+
+var endingKinds = map[string]engine.EndingKind{
+	"":        engine.Neutral,
+	"neutral": engine.Neutral,
+	"win":     engine.Win,
+	"lose":    engine.Lose}
+
+var predefined = []*engine.Action{
+	engine.LOOK,
+	engine.INVENTORY,
+	engine.EXAMINE,
+	engine.OPEN,
+	engine.CLOSE,
+	engine.TAKE,
+	engine.PUT,
+	engine.DROP,
+	engine.USE,
+	engine.ASK,
+	engine.UNLOCK,
+	engine.LOCK,
+	engine.GIVE,
+	engine.LIGHT,
+	engine.EXTINGUISH}
+
+type scriptable interface {
+	attach(scripts map[string]string)
+}
+
+type reference struct {
+	path string
+	name string
+}
+
+type builder struct {
+	src     *source
+	story   *Story
+	items   map[string]bool
+	keys    []reference
+	topics  []reference
+	holders []reference
+}
+
+//LoadFile - reads story from JSON file
+func LoadFile(fileName string) (*Story, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Load(f, fileName)
+}
+
+//Load - reads story from JSON, file name is used in error messages only.
+//Returns ErrorList, if the story has any problems.
+func Load(r io.Reader, fileName string) (*Story, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	src := newSource(fileName, data)
+
+	var story storyData
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(&story); err != nil {
+		offset := decoder.InputOffset()
+		switch e := err.(type) {
+		case *json.SyntaxError:
+			offset = e.Offset
+		case *json.UnmarshalTypeError:
+			offset = e.Offset
+		}
+		src.errorAt(offset, strings.TrimPrefix(err.Error(), "json: "))
+		return nil, src.errors
+	}
+
+	b := &builder{src: src, items: make(map[string]bool)}
+	b.build(&story)
+
+	if len(src.errors) > 0 {
+		return nil, src.errors
+	}
+	return b.story, nil
+}
+
+func (b *builder) build(data *storyData) {
+	b.story = &Story{
+		Title:     data.Title,
+		IntroText: data.Intro,
+		HelpText:  data.Help}
+	b.story.Rooms = make(map[string]engine.Spacer)
+
+	for i, action := range data.Actions {
+		if action.Name == "" {
+			b.src.errorf(fmt.Sprintf("actions[%d]", i), "action has no name")
+		}
+		b.story.Actions = append(b.story.Actions, engine.Action{
+			Name:               strings.ToLower(action.Name),
+			IsItemRequired:     action.ItemRequired,
+			IsTargetRequired:   action.TargetRequired,
+			IsActorRequired:    action.ActorRequired,
+			IsActorTarget:      action.ActorTarget,
+			IsTopicRequired:    action.TopicRequired,
+			Syntax:             action.Syntax,
+			DefaultTopicAnswer: action.DefaultTopicAnswer})
+	}
+
+	for i, verb := range data.Verbs {
+		path := fmt.Sprintf("verbs[%d]", i)
+		if err := engine.CheckPattern(verb.Pattern); err != nil {
+			b.src.errorf(join(path, "pattern"), "%v", err)
+		}
+		action := b.findAction(strings.ToLower(verb.Action))
+		if action == nil {
+			b.src.errorf(join(path, "action"), "unknown action %q", verb.Action)
+			continue
+		}
+		b.story.AddVerb(verb.Pattern, action)
+	}
+
+	for i, award := range data.Awards {
+		path := fmt.Sprintf("awards[%d]", i)
+		if award.Name == "" {
+			b.src.errorf(path, "award has no name")
+		} else if b.findAward(award.Name) != nil {
+			b.src.errorf(join(path, "name"), "duplicate award %q", award.Name)
+		}
+		b.story.Awards = append(b.story.Awards, award)
+	}
+
+	for i, ending := range data.Endings {
+		path := fmt.Sprintf("endings[%d]", i)
+		if ending.ID == "" {
+			b.src.errorf(path, "ending has no id")
+		} else if b.findEnding(ending.ID) != nil {
+			b.src.errorf(join(path, "id"), "duplicate ending %q", ending.ID)
+		}
+		kind, ok := endingKinds[ending.Kind]
+		if !ok {
+			b.src.errorf(join(path, "kind"), "unknown ending kind %q, should be win, lose or neutral", ending.Kind)
+		}
+		b.story.Endings = append(b.story.Endings, engine.Ending{ID: ending.ID, Kind: kind, Text: ending.Text})
+	}
+	b.story.Amusing = data.Amusing
+	b.story.ShowExits = data.ShowExits
+
+	for i, data := range data.Rooms {
+		path := fmt.Sprintf("rooms[%d]", i)
+		if data.Name == "" {
+			b.src.errorf(path, "room has no name")
+			continue
+		}
+		if b.story.Rooms[data.Name] != nil {
+			b.src.errorf(path+".name", "duplicate room %q", data.Name)
+			continue
+		}
+
+		scripts := b.compile(data.scripts(), path, data.Type)
+		roomType := data.Type
+		if len(scripts) > 0 {
+			roomType = "scriptedRoom"
+		}
+
+		var room engine.Spacer = &engine.Room{}
+		if roomType != "" {
+			if room = engine.NewRoom(roomType, &b.story.Game); room == nil {
+				b.src.errorf(path+".type", "unknown room type %q", data.Type)
+				continue
+			}
+		}
+
+		*room.BasicRoom() = engine.Room{
+			Desc:     data.Desc,
+			Exits:    make(map[string]string),
+			Blocked:  b.buildBlocked(data.Blocked),
+			Locked:   data.Locked,
+			IsDark:   data.Dark,
+			DarkDesc: data.DarkDesc,
+			Items:    b.buildItems(data.Items, data.Name, path+".items")}
+		if len(scripts) > 0 {
+			room.(scriptable).attach(scripts)
+		}
+		b.story.Rooms[data.Name] = room
+	}
+
+	for i, data := range data.Rooms {
+		room := b.story.Rooms[data.Name]
+		if room != nil {
+			b.buildExits(room.BasicRoom(), data.Exits, fmt.Sprintf("rooms[%d].exits", i))
+			b.buildHiddenExits(room.BasicRoom(), data.HiddenExits, fmt.Sprintf("rooms[%d].hiddenExits", i))
+		}
+	}
+
+	for i := range data.Doors {
+		b.buildDoor(&data.Doors[i], fmt.Sprintf("doors[%d]", i))
+	}
+
+	b.story.Inventory = b.buildItems(data.Inventory, "inventory", "inventory")
+
+	b.story.Location = data.Start
+	if data.Start == "" {
+		b.src.errorf("", "start room is not set")
+	} else if b.story.Rooms[data.Start] == nil {
+		b.src.errorf("start", "unknown start room %q", data.Start)
+	}
+
+	b.checkReferences()
+
+	for i := range data.Rules {
+		b.buildRule(&data.Rules[i], fmt.Sprintf("rules[%d]", i))
+	}
+}
+
+func (b *builder) buildRule(data *ruleData, path string) {
+	phase, ok := phases[data.Phase]
+	if !ok {
+		b.src.errorf(join(path, "phase"), "unknown phase %q, should be before, instead or after", data.Phase)
+	}
+	if data.Action != "go" && b.findAction(data.Action) == nil {
+		b.src.errorf(join(path, "action"), "unknown action %q", data.Action)
+	}
+	if data.Room != "" {
+		b.checkRoom(join(path, "room"), data.Room)
+	}
+
+	for i, condition := range data.When {
+		at := fmt.Sprintf("%s.when[%d]", path, i)
+		b.checkItem(join(at, "item"), condition.Item)
+		b.checkOwner(join(at, "in"), condition.In)
+		b.checkFlag(join(at, "flag"), condition.Flag)
+		if condition.Visited != "" {
+			b.checkRoom(join(at, "visited"), condition.Visited)
+		}
+	}
+
+	for i, effect := range data.Then {
+		at := fmt.Sprintf("%s.then[%d]", path, i)
+		b.checkItem(join(at, "item"), effect.Item)
+		b.checkOwner(join(at, "moveTo"), effect.MoveTo)
+		b.checkFlag(join(at, "set"), effect.Set)
+		b.checkFlag(join(at, "clear"), effect.Clear)
+		if effect.Award != "" && b.findAward(effect.Award) == nil {
+			b.src.errorf(join(at, "award"), "unknown award %q", effect.Award)
+		}
+		if effect.Finish != "" && b.findEnding(effect.Finish) == nil {
+			b.src.errorf(join(at, "finish"), "unknown ending %q", effect.Finish)
+		}
+	}
+
+	b.story.Rules = append(b.story.Rules, engine.Rule{
+		Phase:  phase,
+		Action: data.Action,
+		Object: data.Object,
+		Target: data.Target,
+		Topic:  data.Topic,
+		Room:   data.Room,
+		Stop:   data.Stop,
+		When:   data.When,
+		Then:   data.Then})
+}
+
+func (b *builder) checkRoom(path string, name string) {
+	if b.story.Rooms[name] == nil {
+		b.src.errorf(path, "unknown room %q", name)
+	}
+}
+
+func (b *builder) checkItem(path string, name string) {
+	if name != "" && !b.items[name] {
+		b.src.errorf(path, "unknown item %q", name)
+	}
+}
+
+func (b *builder) checkOwner(path string, name string) {
+	if name != "" && name != "inventory" && !b.items[name] && b.story.Rooms[name] == nil {
+		b.src.errorf(path, "unknown room or item %q", name)
+	}
+}
+
+func (b *builder) checkFlag(path string, name string) {
+	if name != "" && (&engine.Item{}).Flag(name) == nil {
+		b.src.errorf(path, "unknown flag %q", name)
+	}
+}
+
+func (b *builder) buildExits(room *engine.Room, exits map[string]string, path string) {
+	directions := []string{}
+	for dir := range exits {
+		directions = append(directions, dir)
+	}
+	sort.Strings(directions)
+
+	for _, dir := range directions {
+		target := exits[dir]
+		if b.story.Rooms[target] == nil {
+			b.src.errorf(join(path, dir), "exit %s leads to unknown room %q", dir, target)
+			continue
+		}
+
+		name := engine.Direction(dir)
+		if name == "" {
+			name = dir //named exit, like "ladder"
+		}
+		if _, ok := room.Exits[name]; ok {
+			b.src.errorf(join(path, dir), "duplicate exit %s", name)
+			continue
+		}
+		room.Exits[name] = target
+	}
+}
+
+func (b *builder) buildDoor(data *doorData, path string) {
+	if data.Name == "" {
+		b.src.errorf(path, "door has no name")
+		return
+	}
+	b.items[data.Name] = true
+
+	if len(data.Connects) != 2 {
+		b.src.errorf(join(path, "connects"), "door should connect two rooms")
+		return
+	}
+	for i, name := range data.Connects {
+		if b.story.Rooms[name] == nil {
+			b.src.errorf(fmt.Sprintf("%s.connects[%d]", path, i), "unknown room %q", name)
+			return
+		}
+	}
+	if !b.hasExit(data.Connects[0], data.Connects[1]) && !b.hasExit(data.Connects[1], data.Connects[0]) {
+		b.src.errorf(join(path, "connects"), "there is no exit between %q and %q", data.Connects[0], data.Connects[1])
+	}
+
+	if data.Key != "" {
+		b.keys = append(b.keys, reference{join(path, "key"), data.Key})
+	}
+
+	var door engine.Doorer = &engine.Door{}
+	if data.Type != "" {
+		item, ok := engine.NewItem(data.Type, &b.story.Game).(engine.Doorer)
+		if !ok {
+			b.src.errorf(join(path, "type"), "unknown door type %q", data.Type)
+			return
+		}
+		door = item
+	}
+
+	*door.BasicDoor() = engine.Door{
+		Item: engine.Item{
+			Name:              data.Name,
+			AName:             data.AName,
+			Desc:              data.Desc,
+			Vocab:             data.Vocab,
+			KeyName:           data.Key,
+			IsVisible:         true,
+			IsOpen:            data.Open,
+			IsLocked:          data.Locked,
+			DefaultActionDesc: data.ActionDesc},
+		Connects: [2]string{data.Connects[0], data.Connects[1]}}
+	b.story.AddDoor(door)
+}
+
+func (b *builder) buildHiddenExits(room *engine.Room, hidden []string, path string) {
+	for i, dir := range hidden {
+		if name := engine.Direction(dir); name != "" {
+			dir = name
+		}
+		if _, ok := room.Exits[dir]; !ok {
+			b.src.errorf(fmt.Sprintf("%s[%d]", path, i), "unknown exit %q", dir)
+			continue
+		}
+		room.HiddenExits = append(room.HiddenExits, dir)
+	}
+}
+
+//buildBlocked - messages by direction, "n" and "north" are the same
+func (b *builder) buildBlocked(blocked map[string]string) map[string]string {
+	result := make(map[string]string)
+	for dir, msg := range blocked {
+		if name := engine.Direction(dir); name != "" {
+			dir = name
+		}
+		result[dir] = msg
+	}
+	return result
+}
+
+func (b *builder) hasExit(from string, to string) bool {
+	for _, target := range b.story.Rooms[from].BasicRoom().Exits {
+		if target == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *builder) buildItems(list []itemData, location string, path string) []engine.Itemer {
+	result := []engine.Itemer{}
+	for i := range list {
+		item := b.buildItem(&list[i], location, fmt.Sprintf("%s[%d]", path, i))
+		if item != nil {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func (b *builder) buildItem(data *itemData, location string, path string) engine.Itemer {
+	if data.Name == "" {
+		b.src.errorf(path, "item has no name")
+		return nil
+	}
+	b.items[data.Name] = true
+
+	item := engine.Item{
+		Name:              data.Name,
+		AName:             data.AName,
+		Desc:              data.Desc,
+		Vocab:             data.Vocab,
+		KeyName:           data.Key,
+		Location:          location,
+		IsVisible:         !data.Hidden,
+		IsDisabled:        data.Disabled,
+		IsDecoration:      data.Decoration,
+		IsSurface:         data.Surface,
+		IsContainer:       data.Container,
+		IsPickable:        data.Pickable,
+		IsOpen:            data.Open,
+		IsLocked:          data.Locked,
+		IsUnbreakableName: data.UnbreakableName,
+		IsUseTarget:       data.UseTarget,
+		IsLightSource:     data.LightSource,
+		IsLit:             data.Lit,
+		IsPlural:          data.Plural,
+		DefaultActionDesc: data.ActionDesc,
+		CanContainOnly:    data.CanContainOnly}
+
+	if data.Key != "" {
+		b.keys = append(b.keys, reference{join(path, "key"), data.Key})
+	}
+	for i, name := range data.CanContainOnly {
+		b.holders = append(b.holders, reference{fmt.Sprintf("%s.canContainOnly[%d]", path, i), name})
+	}
+
+	item.Items = b.buildItems(data.Items, data.Name, path+".items")
+
+	scripts := b.compile(data.scripts(), path, data.Type)
+	itemType := data.Type
+	if len(scripts) > 0 {
+		itemType = "scriptedItem"
+		if data.isActor() {
+			itemType = "scriptedPerson"
+		}
+	}
+
+	obj := engine.Itemer(&item)
+	if itemType == "" && data.isActor() {
+		obj = &engine.Person{}
+	}
+	if itemType != "" {
+		if obj = engine.NewItem(itemType, &b.story.Game); obj == nil {
+			b.src.errorf(join(path, "type"), "unknown item type %q", data.Type)
+			return nil
+		}
+	}
+	if len(scripts) > 0 {
+		obj.(scriptable).attach(scripts)
+	}
+
+	actor, isActor := obj.(engine.Actor)
+	if !isActor {
+		if data.isActor() {
+			b.src.errorf(join(path, "type"), "type %q is not an actor", data.Type)
+			return nil
+		}
+		*obj.Basic() = item
+		return obj
+	}
+
+	if data.Gender != "" && data.Gender != "male" && data.Gender != "female" {
+		b.src.errorf(join(path, "gender"), "gender should be \"male\" or \"female\"")
+	}
+
+	person := actor.BasicPerson()
+	*person = engine.Person{
+		Item:           item,
+		NameEx:         data.NameEx,
+		Hello:          data.Hello,
+		IsKnown:        data.Known,
+		Gender:         data.Gender,
+		DefaultAnswers: data.DefaultAnswers}
+
+	for i, topic := range data.Topics {
+		b.topics = append(b.topics, reference{fmt.Sprintf("%s.topics[%d].action", path, i), topic.Action})
+		person.Topics = append(person.Topics, &engine.Topic{
+			Action:         topic.Action,
+			Vocab:          topic.Vocab,
+			Answers:        topic.Answers,
+			RepeatAnswers:  topic.RepeatAnswers,
+			IsItemConsumed: topic.ItemConsumed})
+	}
+
+	return actor
+}
+
+//compile checks scripts, they can't be mixed with custom types
+func (b *builder) compile(scripts map[string]string, path string, objType string) map[string]string {
+	for hook, src := range scripts {
+		if objType != "" {
+			b.src.errorf(join(path, hook), "%s script can't be used with type %q", hook, objType)
+			delete(scripts, hook)
+			continue
+		}
+		if _, err := script.Compile(src); err != nil {
+			b.src.errorf(join(path, hook), "%s script: %v", hook, err)
+		}
+	}
+	return scripts
+}
+
+func (b *builder) checkReferences() {
+	for _, ref := range b.keys {
+		if !b.items[ref.name] {
+			b.src.errorf(ref.path, "unknown key %q", ref.name)
+		}
+	}
+
+	for _, ref := range b.holders {
+		if !b.items[ref.name] {
+			b.src.errorf(ref.path, "unknown item %q", ref.name)
+		}
+	}
+
+	for _, ref := range b.topics {
+		if b.findAction(ref.name) == nil {
+			b.src.errorf(ref.path, "unknown action %q", ref.name)
+		}
+	}
+}
+
+func (b *builder) findAward(name string) *engine.Award {
+	for i := range b.story.Awards {
+		if b.story.Awards[i].Name == name {
+			return &b.story.Awards[i]
+		}
+	}
+	return nil
+}
+
+func (b *builder) findEnding(id string) *engine.Ending {
+	for i := range b.story.Endings {
+		if b.story.Endings[i].ID == id {
+			return &b.story.Endings[i]
+		}
+	}
+	return nil
+}
+
+func (b *builder) findAction(name string) *engine.Action {
+	for _, action := range predefined {
+		if action.Name == name {
+			return action
+		}
+	}
+	for i := range b.story.Actions {
+		if b.story.Actions[i].Name == name {
+			return &b.story.Actions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package storyloader
+
+import (
+	"storyteller/engine"
+	"storyteller/script"
+	"strings"
+)
+
+//script hooks
+const (
+	onAction = "onAction"
+	onTopic  = "onTopic"
+	onEnter  = "onEnter"
+	onLeave  = "onLeave"
+)
+
+func init() {
+	engine.RegisterItem("scriptedItem", func(game *engine.Game) engine.Itemer {
+		return &scriptedItem{handlers: handlers{game: game}}
+	})
+	engine.RegisterActor("scriptedPerson", func(game *engine.Game) engine.Actor {
+		return &scriptedPerson{handlers: handlers{game: game}}
+	})
+	engine.RegisterRoom("scriptedRoom", func(game *engine.Game) engine.Spacer {
+		return &scriptedRoom{handlers: handlers{game: game}}
+	})
+}
+
+//handlers keeps script sources by hook name, sources are saved with the game
+type handlers struct {
+	Scripts  map[string]string
+	game     *engine.Game
+	programs map[string]*script.Program
+}
+
+func (h *handlers) attach(scripts map[string]string) {
+	h.Scripts = scripts
+}
+
+//run executes the hook script, returns true if script handled the event itself
+func (h *handlers) run(hook string, vars map[string]script.Value) (bool, string) {
+	return h.execute(hook, vars, newAPI(h.game))
+}
+
+//probe executes the hook script without effects, returns true if script would handle the event
+func (h *handlers) probe(hook string, vars map[string]script.Value) bool {
+	api := newAPI(h.game)
+	api.isProbe = true
+	handled, _ := h.execute(hook, vars, api)
+	return handled
+}
+
+func (h *handlers) execute(hook string, vars map[string]script.Value, api *api) (bool, string) {
+	src, ok := h.Scripts[hook]
+	if !ok {
+		return false, ""
+	}
+
+	if h.programs == nil {
+		h.programs = make(map[string]*script.Program)
+	}
+	program, ok := h.programs[hook]
+	if !ok {
+		var err error
+		if program, err = script.Compile(src); err != nil {
+			return false, "[" + hook + " script: " + err.Error() + "]"
+		}
+		h.programs[hook] = program
+	}
+
+	result, err := program.Run(&script.Env{Vars: vars, Funcs: api.funcs()})
+	msg := strings.Join(api.output, "\n")
+	if err != nil {
+		msg = concat(msg, "["+hook+" script: "+err.Error()+"]", "\n")
+	}
+
+	return script.IsTrue(result), msg
+}
+
+func concat(a string, b string, separator string) string {
+	if a == "" || b == "" {
+		return a + b
+	}
+	return a + separator + b
+}
+
+func nameOf(item engine.Itemer) script.Value {
+	if item == nil {
+		return nil
+	}
+	return item.Basic().Name
+}
+
+///////////////////////////////////////////////////////////////////////////////
+type scriptedItem struct {
+	engine.Item
+	handlers
+}
+
+//OnAction runs script before the default behaviour, script can replace it returning true
+func (item *scriptedItem) OnAction(action *engine.Action, target engine.Itemer) (string, string) {
+	handled, msg := item.run(onAction, map[string]script.Value{
+		"self":   item.Name,
+		"action": action.Name,
+		"target": nameOf(target)})
+
+	if handled {
+		return msg, item.Location
+	}
+
+	result, location := item.Item.OnAction(action, target)
+	return concat(result, msg, "\n"), location
+}
+
+///////////////////////////////////////////////////////////////////////////////
+type scriptedPerson struct {
+	engine.Person
+	handlers
+}
+
+//OnAction runs script before the default behaviour, script can replace it returning true
+func (person *scriptedPerson) OnAction(action *engine.Action, target engine.Itemer) (string, string) {
+	handled, msg := person.run(onAction, map[string]script.Value{
+		"self":   person.Name,
+		"action": action.Name,
+		"target": nameOf(target)})
+
+	if handled {
+		return msg, person.Location
+	}
+
+	result, location := person.Person.OnAction(action, target)
+	return concat(result, msg, "\n"), location
+}
+
+//OnTopic runs script before the default answer, script can replace it returning true
+func (person *scriptedPerson) OnTopic(topic *engine.Topic, action *engine.Action, item engine.Itemer) string {
+	vars := map[string]script.Value{
+		"self":   person.Name,
+		"action": action.Name,
+		"topic":  nil,
+		"item":   nameOf(item)}
+	if topic != nil {
+		vars["topic"] = topic.Vocab
+	}
+
+	handled, msg := person.run(onTopic, vars)
+	if handled {
+		return msg
+	}
+
+	return concat(person.Person.OnTopic(topic, action, item), msg, "\n")
+}
+
+///////////////////////////////////////////////////////////////////////////////
+type scriptedRoom struct {
+	engine.Room
+	handlers
+}
+
+//LeaveRoom - script returns true to keep the player in the room
+func (room *scriptedRoom) LeaveRoom(dir string) (bool, string) {
+	blocked, msg := room.run(onLeave, map[string]script.Value{"dir": dir})
+	if blocked {
+		return false, msg
+	}
+	if msg != "" {
+		msg += "\n"
+	}
+	return true, msg
+}
+
+//CanLeave - the onLeave script runs without effects, "say", "move", "award" and others do nothing
+func (room *scriptedRoom) CanLeave(dir string) bool {
+	return !room.probe(onLeave, map[string]script.Value{"dir": dir})
+}
+
+//EnterRoom - script output follows room description, script can replace it returning true
+func (room *scriptedRoom) EnterRoom(name string) string {
+	first := !room.IsVisited
+	desc := room.Room.EnterRoom(name)
+
+	handled, msg := room.run(onEnter, map[string]script.Value{
+		"room":  name,
+		"first": first})
+	if handled {
+		return msg
+	}
+	return concat(desc, msg, "\n")
+}
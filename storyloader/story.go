@@ -0,0 +1,183 @@
+package storyloader
+
+import (
+	"storyteller/engine"
+)
+
+//Story - game loaded from a story file
+type Story struct {
+	engine.Game
+	Title     string
+	IntroText string
+	HelpText  string
+}
+
+//Intro for the story
+func (story *Story) Intro() string {
+	story.Begin()
+	if story.IntroText == "" {
+		return story.Game.Intro()
+	}
+	return "\n\n" + story.IntroText + "\n\n" + story.EnterRoom() + "\n"
+}
+
+//Help - engine help with story specific notes
+func (story *Story) Help() string {
+	if story.HelpText == "" {
+		return story.Game.Help()
+	}
+	return story.Game.Help() + "\n\n" + story.HelpText
+}
+
+type storyData struct {
+	Title     string         `json:"title"`
+	Intro     string         `json:"intro"`
+	Help      string         `json:"help"`
+	Start     string         `json:"start"`
+	Actions   []actionData   `json:"actions"`
+	Verbs     []verbData     `json:"verbs"`
+	Rules     []ruleData     `json:"rules"`
+	Awards    []engine.Award `json:"awards"`
+	Endings   []endingData   `json:"endings"`
+	Amusing   string         `json:"amusing"`
+	ShowExits bool           `json:"showExits"`
+	Rooms     []roomData     `json:"rooms"`
+	Doors     []doorData     `json:"doors"`
+	Inventory []itemData     `json:"inventory"`
+}
+
+type actionData struct {
+	Name               string `json:"name"`
+	ItemRequired       bool   `json:"itemRequired"`
+	TargetRequired     bool   `json:"targetRequired"`
+	ActorRequired      bool   `json:"actorRequired"`
+	ActorTarget        bool   `json:"actorTarget"`
+	TopicRequired      bool   `json:"topicRequired"`
+	Syntax             string `json:"syntax"`
+	DefaultTopicAnswer string `json:"defaultTopicAnswer"`
+}
+
+type endingData struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+	Text string `json:"text"`
+}
+
+type verbData struct {
+	Pattern string `json:"pattern"`
+	Action  string `json:"action"`
+}
+
+type ruleData struct {
+	Phase  string             `json:"phase"`
+	Action string             `json:"action"`
+	Object string             `json:"object"`
+	Target string             `json:"target"`
+	Topic  string             `json:"topic"`
+	Room   string             `json:"room"`
+	Stop   bool               `json:"stop"`
+	When   []engine.Condition `json:"when"`
+	Then   []engine.Effect    `json:"then"`
+}
+
+type roomData struct {
+	Type        string            `json:"type"`
+	Name        string            `json:"name"`
+	Desc        string            `json:"desc"`
+	Exits       map[string]string `json:"exits"`
+	Blocked     map[string]string `json:"blocked"`
+	HiddenExits []string          `json:"hiddenExits"`
+	Locked      string            `json:"locked"`
+	Items       []itemData        `json:"items"`
+
+	Dark     bool   `json:"dark"`
+	DarkDesc string `json:"darkDesc"`
+
+	OnEnter string `json:"onEnter"`
+	OnLeave string `json:"onLeave"`
+}
+
+type doorData struct {
+	Type     string   `json:"type"`
+	Name     string   `json:"name"`
+	AName    string   `json:"aname"`
+	Desc     string   `json:"desc"`
+	Vocab    string   `json:"vocab"`
+	Key      string   `json:"key"`
+	Connects []string `json:"connects"`
+	Open     bool     `json:"open"`
+	Locked   bool     `json:"locked"`
+
+	ActionDesc map[string]string `json:"actionDesc"`
+}
+
+type itemData struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	AName string `json:"aname"`
+	Desc  string `json:"desc"`
+	Vocab string `json:"vocab"`
+	Key   string `json:"key"`
+
+	Hidden          bool `json:"hidden"`
+	Disabled        bool `json:"disabled"`
+	Decoration      bool `json:"decoration"`
+	Surface         bool `json:"surface"`
+	Container       bool `json:"container"`
+	Pickable        bool `json:"pickable"`
+	Open            bool `json:"open"`
+	Locked          bool `json:"locked"`
+	UnbreakableName bool `json:"unbreakableName"`
+	UseTarget       bool `json:"useTarget"`
+	LightSource     bool `json:"lightSource"`
+	Lit             bool `json:"lit"`
+	Plural          bool `json:"plural"`
+
+	ActionDesc     map[string]string `json:"actionDesc"`
+	CanContainOnly []string          `json:"canContainOnly"`
+	Items          []itemData        `json:"items"`
+	OnAction       string            `json:"onAction"`
+
+	//actor only
+	Actor          bool                `json:"actor"`
+	NameEx         string              `json:"nameEx"`
+	Hello          string              `json:"hello"`
+	Known          bool                `json:"known"`
+	Gender         string              `json:"gender"`
+	Topics         []topicData         `json:"topics"`
+	DefaultAnswers map[string][]string `json:"defaultAnswers"`
+	OnTopic        string              `json:"onTopic"`
+}
+
+type topicData struct {
+	Action        string   `json:"action"`
+	Vocab         string   `json:"vocab"`
+	Answers       []string `json:"answers"`
+	RepeatAnswers []string `json:"repeatAnswers"`
+	ItemConsumed  bool     `json:"itemConsumed"`
+}
+
+func (data *itemData) isActor() bool {
+	return data.Actor || len(data.Topics) > 0 || len(data.DefaultAnswers) > 0 || data.OnTopic != "" || data.Gender != ""
+}
+
+func (data *itemData) scripts() map[string]string {
+	return scripts(map[string]string{
+		onAction: data.OnAction,
+		onTopic:  data.OnTopic})
+}
+
+func (data *roomData) scripts() map[string]string {
+	return scripts(map[string]string{
+		onEnter: data.OnEnter,
+		onLeave: data.OnLeave})
+}
+
+func scripts(hooks map[string]string) map[string]string {
+	for hook, src := range hooks {
+		if src == "" {
+			delete(hooks, hook)
+		}
+	}
+	return hooks
+}
@@ -5,24 +5,93 @@ import (
 	"fmt"
 	"os"
 	"storyteller/engine"
-	"storyteller/game"
+	"strings"
 
 	"github.com/logrusorgru/aurora"
 )
 
+const defaultSaveName = "savegame"
+
 func main() {
-	context := game.Sample()
+	context, err := newGame()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	context.BasicGame().Begin()
 	fmt.Println(aurora.Faint(context.Intro()))
 
 	scanner := bufio.NewScanner(os.Stdin)
 	for scanner.Scan() {
-		if scanner.Text() == "restart" {
-			context = game.Sample()
-			fmt.Println(aurora.Faint(context.Intro()))
-			continue
+		words := strings.Fields(strings.ToLower(scanner.Text()))
+		if len(words) > 0 {
+			switch words[0] {
+			case "save":
+				fmt.Println(aurora.Faint(save(context, words[1:]) + "\n"))
+				continue
+			case "restore":
+				fmt.Println(aurora.Faint(restore(&context, words[1:]) + "\n"))
+				continue
+			}
 		}
 		msg := engine.Process(context, scanner.Text())
-		fmt.Println(aurora.Faint(msg + "\n"))
+		printEnding(context.BasicGame().Ending, msg+"\n")
+	}
+
+}
+
+//printEnding highlights the ending banner
+func printEnding(ending *engine.Ending, msg string) {
+	if ending == nil || !strings.Contains(msg, ending.Banner()) {
+		fmt.Println(aurora.Faint(msg))
+		return
 	}
 
+	parts := strings.SplitN(msg, ending.Banner(), 2)
+	banner := aurora.Bold(aurora.Yellow(ending.Banner()))
+	switch ending.Kind {
+	case engine.Win:
+		banner = aurora.Bold(aurora.Green(ending.Banner()))
+	case engine.Lose:
+		banner = aurora.Bold(aurora.Red(ending.Banner()))
+	}
+	fmt.Printf("%s%s%s\n", aurora.Faint(parts[0]), banner, aurora.Faint(parts[1]))
+}
+
+func saveFileName(args []string) string {
+	if len(args) > 0 {
+		return args[0] + ".json"
+	}
+	return defaultSaveName + ".json"
+}
+
+func save(context engine.Adventurer, args []string) string {
+	f, err := os.Create(saveFileName(args))
+	if err != nil {
+		return "Can't save the game: " + err.Error()
+	}
+	defer f.Close()
+
+	if err = context.BasicGame().Save(f); err != nil {
+		return "Can't save the game: " + err.Error()
+	}
+	return "Saved."
+}
+
+func restore(context *engine.Adventurer, args []string) string {
+	f, err := os.Open(saveFileName(args))
+	if err != nil {
+		return "Can't restore the game: " + err.Error()
+	}
+	defer f.Close()
+
+	restored, err := newGame()
+	if err != nil {
+		return "Can't restore the game: " + err.Error()
+	}
+	if err = restored.BasicGame().Load(f); err != nil {
+		return "Can't restore the game: " + err.Error()
+	}
+	*context = restored
+	return "Restored.\n\n" + restored.BasicGame().Look()
 }
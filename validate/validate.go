@@ -0,0 +1,247 @@
+//Package validate finds mistakes in the story world: broken exits, misplaced items, missing keys,
+//topics without answers and items which can't be told apart. It's meant for story tests:
+//
+//	if problems := validate.Check(story.BasicGame()); validate.HasErrors(problems) {
+//		t.Error(problems)
+//	}
+package validate
+
+import (
+	"fmt"
+	"sort"
+	"storyteller/engine"
+	"strings"
+)
+
+//Severity of the problem
+type Severity int
+
+//Error breaks the game, Warning is most likely a mistake, but the game may handle it in code
+const (
+	Warning Severity = iota
+	Error
+)
+
+func (severity Severity) String() string {
+	if severity == Error {
+		return "error"
+	}
+	return "warning"
+}
+
+//Problem - single finding
+type Problem struct {
+	Severity Severity
+	Where    string //"room Cave", "item box", etc.
+	Message  string
+}
+
+func (problem Problem) String() string {
+	return problem.Severity.String() + ": " + problem.Where + ": " + problem.Message
+}
+
+type checker struct {
+	game     *engine.Game
+	problems []Problem
+	names    map[string]bool //all item names
+	keys     map[string][]string
+	seen     map[engine.Itemer]bool
+}
+
+//Check - walks the game world and reports problems, rooms are checked in alphabetical order
+func Check(game *engine.Game) []Problem {
+	c := &checker{
+		game:  game,
+		names: make(map[string]bool),
+		keys:  make(map[string][]string),
+		seen:  make(map[engine.Itemer]bool)}
+
+	if game.Rooms[game.Location] == nil {
+		c.report(Error, "game", "start location %q is not a room", game.Location)
+	}
+
+	rooms := []string{}
+	for name := range game.Rooms {
+		rooms = append(rooms, name)
+	}
+	sort.Strings(rooms)
+
+	for _, name := range rooms {
+		c.room(name)
+	}
+	c.items("inventory", "inventory", game.Inventory)
+	c.ambiguous("inventory", game.Inventory, nil)
+
+	holders := []string{}
+	for key := range c.keys {
+		holders = append(holders, key)
+	}
+	sort.Strings(holders)
+	for _, key := range holders {
+		if !c.names[key] {
+			for _, name := range c.keys[key] {
+				c.report(Warning, "item "+name, "there is no key %q, it has to appear during the game", key)
+			}
+		}
+	}
+
+	return c.problems
+}
+
+//HasErrors - true if any problem is an Error
+func HasErrors(problems []Problem) bool {
+	for _, problem := range problems {
+		if problem.Severity == Error {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *checker) room(name string) {
+	room := c.game.Rooms[name].BasicRoom()
+	where := "room " + name
+
+	dirs := []string{}
+	for dir := range room.Exits {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		if c.game.Rooms[room.Exits[dir]] == nil {
+			c.report(Error, where, "exit %s leads to unknown room %q", dir, room.Exits[dir])
+		}
+	}
+
+	for _, dir := range room.HiddenExits {
+		if _, ok := room.Exits[dir]; !ok {
+			c.report(Warning, where, "hidden exit %s doesn't exist", dir)
+		}
+	}
+
+	c.items(name, name, room.Items)
+	c.ambiguous(where, room.Items, c.game.Inventory)
+}
+
+//items - checks items held by the owner, owner is a room name, an item name or "inventory"
+func (c *checker) items(owner string, room string, items []engine.Itemer) {
+	for _, i := range items {
+		item := i.Basic()
+		where := "item " + item.Name
+
+		if door, ok := i.(engine.Doorer); ok {
+			c.door(door, room)
+			if c.seen[i] {
+				continue
+			}
+		} else if item.Location != owner {
+			c.report(Error, where, "location is %q, but it's held by %q", item.Location, owner)
+		}
+
+		if c.seen[i] {
+			c.report(Error, where, "the same item is placed twice")
+			continue
+		}
+		c.seen[i] = true
+		c.names[item.Name] = true
+
+		if item.KeyName != "" {
+			c.keys[item.KeyName] = append(c.keys[item.KeyName], item.Name)
+		}
+		if actor, ok := i.(engine.Actor); ok {
+			c.topics(where, actor)
+		}
+
+		c.items(item.Name, room, item.Items)
+	}
+}
+
+func (c *checker) door(door engine.Doorer, room string) {
+	base := door.BasicDoor()
+	where := "item " + base.Name
+
+	if base.Connects[0] != room && base.Connects[1] != room {
+		c.report(Error, where, "door is placed in %q, but connects %q and %q", room, base.Connects[0], base.Connects[1])
+	}
+	if c.seen[door] {
+		return
+	}
+	if base.Location != base.Connects[0] && base.Location != base.Connects[1] {
+		c.report(Error, where, "location is %q, but the door connects %q and %q", base.Location, base.Connects[0], base.Connects[1])
+	}
+	for _, name := range base.Connects {
+		if c.game.Rooms[name] == nil {
+			c.report(Error, where, "door connects unknown room %q", name)
+		}
+	}
+}
+
+func (c *checker) topics(where string, actor engine.Actor) {
+	for _, topic := range actor.BasicPerson().Topics {
+		if len(topic.Answers) == 0 {
+			c.report(Warning, where, "%s topic %q has no answers", topic.Action, topic.Vocab)
+		}
+	}
+}
+
+//ambiguous - items in the same scope which can't be told apart by the player,
+//items are compared with each other and with others, others are checked on their own
+func (c *checker) ambiguous(where string, items []engine.Itemer, others []engine.Itemer) {
+	all := flatten(items)
+	rest := flatten(others)
+	for i, a := range all {
+		for _, b := range append(append([]engine.Itemer{}, all[i+1:]...), rest...) {
+			if a == b {
+				continue
+			}
+			nameA, nameB := a.Basic().Name, b.Basic().Name
+			switch {
+			case strings.EqualFold(nameA, nameB):
+				c.report(Error, where, "two items are named %q", nameA)
+			case subset(words(a), words(b)):
+				c.report(Warning, where, "%q can't be referred without matching %q", nameA, nameB)
+			case subset(words(b), words(a)):
+				c.report(Warning, where, "%q can't be referred without matching %q", nameB, nameA)
+			}
+		}
+	}
+}
+
+func flatten(items []engine.Itemer) []engine.Itemer {
+	result := []engine.Itemer{}
+	for _, i := range items {
+		result = append(result, i)
+		result = append(result, flatten(i.Basic().Items)...)
+	}
+	return result
+}
+
+//words - vocabulary the player uses for the item
+func words(i engine.Itemer) map[string]bool {
+	item := i.Basic()
+	result := make(map[string]bool)
+	for _, word := range strings.Fields(strings.ToLower(item.Vocab)) {
+		result[word] = true
+	}
+	if item.IsUnbreakableName {
+		result[strings.ToLower(item.Name)] = true
+	} else {
+		for _, word := range strings.Fields(strings.ToLower(item.Name)) {
+			result[word] = true
+		}
+	}
+	return result
+}
+
+func subset(a map[string]bool, b map[string]bool) bool {
+	for word := range a {
+		if !b[word] {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *checker) report(severity Severity, where string, format string, args ...interface{}) {
+	c.problems = append(c.problems, Problem{severity, where, fmt.Sprintf(format, args...)})
+}
@@ -0,0 +1,20 @@
+package validate
+
+import (
+	"storyteller/engine"
+	"testing"
+)
+
+func TestInventoryCheckedOnce(t *testing.T) {
+	game := &engine.Game{Location: "Hall", Rooms: make(map[string]engine.Spacer)}
+	game.Rooms["Hall"] = &engine.Room{Desc: "Hall."}
+	game.Rooms["Study"] = &engine.Room{Desc: "Study."}
+	game.Inventory = []engine.Itemer{
+		&engine.Item{Name: "key", Location: "inventory", IsVisible: true},
+		&engine.Item{Name: "old key", Location: "inventory", IsVisible: true}}
+
+	problems := Check(game)
+	if len(problems) != 1 || problems[0].Where != "inventory" {
+		t.Fatalf("the inventory should be reported once: %v", problems)
+	}
+}
@@ -0,0 +1,264 @@
+//Package walkthrough replays recorded commands through engine.Process and compares the responses.
+//
+//Walkthrough file is a plain text:
+//
+//	# comment
+//	@seed 42
+//	expected intro
+//	> look
+//	~ regular expression the response should match
+//	expected response
+//
+//Lines after a command are the snapshot of the response, it's compared line by line ignoring empty lines around.
+//Steps without snapshot are checked only by the regular expressions.
+//The game random source is seeded with Game.SetSeed (1 if the file has no @seed),
+//so the same walkthrough gives the same responses.
+package walkthrough
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"storyteller/engine"
+	"strconv"
+	"strings"
+)
+
+//DefaultSeed is used if the walkthrough has no @seed
+const DefaultSeed = 1
+
+//Step - command and what the response should look like.
+//The first step has empty command, it's the intro.
+type Step struct {
+	Line     int //line of the command in the file
+	Command  string
+	Expect   []string //response snapshot, nil if there's no snapshot
+	Patterns []*regexp.Regexp
+}
+
+//Walkthrough - parsed walkthrough file
+type Walkthrough struct {
+	Seed  int64
+	Steps []*Step
+}
+
+//Divergence - the first response which doesn't match the walkthrough
+type Divergence struct {
+	Step *Step
+	Got  string
+	Diff string
+}
+
+func (d *Divergence) Error() string {
+	what := "> " + d.Step.Command
+	if d.Step.Command == "" {
+		what = "intro"
+	}
+	return fmt.Sprintf("line %d: %s\n%s", d.Step.Line, what, d.Diff)
+}
+
+//Parse - reads walkthrough
+func Parse(r io.Reader) (*Walkthrough, error) {
+	walk := &Walkthrough{Seed: DefaultSeed, Steps: []*Step{{Line: 1}}}
+	step := walk.Steps[0]
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), " \t\r")
+
+		switch {
+		case strings.HasPrefix(text, "#"):
+		case strings.HasPrefix(text, "@seed "):
+			seed, err := strconv.ParseInt(strings.TrimSpace(text[len("@seed "):]), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: bad seed: %v", line, err)
+			}
+			walk.Seed = seed
+		case strings.HasPrefix(text, "@"):
+			return nil, fmt.Errorf("line %d: unknown directive %q", line, text)
+		case text == ">" || strings.HasPrefix(text, "> "):
+			step = &Step{Line: line, Command: strings.TrimSpace(text[1:])}
+			walk.Steps = append(walk.Steps, step)
+		case strings.HasPrefix(text, "~ "):
+			pattern, err := regexp.Compile(text[2:])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			step.Patterns = append(step.Patterns, pattern)
+		default:
+			step.Expect = append(step.Expect, text)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, step := range walk.Steps {
+		step.Expect = trimLines(step.Expect)
+		if len(step.Expect) == 0 {
+			step.Expect = nil
+		}
+	}
+	return walk, nil
+}
+
+//ParseFile - reads walkthrough file
+func ParseFile(fileName string) (*Walkthrough, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	walk, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fileName, err)
+	}
+	return walk, nil
+}
+
+//Run - plays the walkthrough on a new game, returns *Divergence at the first mismatch
+func (walk *Walkthrough) Run(newGame func() engine.Adventurer) error {
+	return walk.play(newGame, func(step *Step, got string) error {
+		return step.check(got)
+	})
+}
+
+//Record - plays the walkthrough and writes it back with actual responses as snapshots, comments are dropped
+func (walk *Walkthrough) Record(newGame func() engine.Adventurer, out io.Writer) error {
+	if _, err := fmt.Fprintf(out, "@seed %d\n", walk.Seed); err != nil {
+		return err
+	}
+
+	return walk.play(newGame, func(step *Step, got string) error {
+		lines := []string{}
+		if step != walk.Steps[0] {
+			lines = append(lines, "> "+step.Command)
+		}
+		for _, pattern := range step.Patterns {
+			lines = append(lines, "~ "+pattern.String())
+		}
+		lines = append(lines, trimLines(splitLines(got))...)
+
+		_, err := fmt.Fprintln(out, strings.Join(lines, "\n"))
+		return err
+	})
+}
+
+func (walk *Walkthrough) play(newGame func() engine.Adventurer, handle func(step *Step, got string) error) error {
+	game := newGame()
+	game.BasicGame().SetSeed(walk.Seed)
+	game.BasicGame().Begin()
+
+	for idx, step := range walk.Steps {
+		var got string
+		if idx == 0 {
+			got = game.Intro()
+		} else {
+			got = engine.Process(game, step.Command)
+		}
+
+		if err := handle(step, got); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//RunFile - parses and plays walkthrough file, handy for story tests:
+//
+//	if err := walkthrough.RunFile("sample.walk", newGame); err != nil {
+//		t.Fatal(err)
+//	}
+func RunFile(fileName string, newGame func() engine.Adventurer) error {
+	walk, err := ParseFile(fileName)
+	if err != nil {
+		return err
+	}
+	if err = walk.Run(newGame); err != nil {
+		return fmt.Errorf("%s: %v", fileName, err)
+	}
+	return nil
+}
+
+func (step *Step) check(got string) error {
+	lines := trimLines(splitLines(got))
+
+	for _, pattern := range step.Patterns {
+		if !pattern.MatchString(got) {
+			return &Divergence{step, got, "response doesn't match ~ " + pattern.String() + "\n" + prefixLines("  ", lines)}
+		}
+	}
+
+	if step.Expect != nil && strings.Join(step.Expect, "\n") != strings.Join(lines, "\n") {
+		return &Divergence{step, got, diff(step.Expect, lines)}
+	}
+	return nil
+}
+
+func splitLines(text string) []string {
+	lines := strings.Split(text, "\n")
+	for idx, line := range lines {
+		lines[idx] = strings.TrimRight(line, " \t\r")
+	}
+	return lines
+}
+
+//trimLines - removes leading and trailing empty lines
+func trimLines(lines []string) []string {
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func prefixLines(prefix string, lines []string) string {
+	result := ""
+	for _, line := range lines {
+		result += prefix + line + "\n"
+	}
+	return result
+}
+
+//diff - line diff based on the longest common subsequence, "-" is expected and "+" is the actual response
+func diff(expect []string, got []string) string {
+	common := make([][]int, len(expect)+1)
+	for i := range common {
+		common[i] = make([]int, len(got)+1)
+	}
+	for i := len(expect) - 1; i >= 0; i-- {
+		for j := len(got) - 1; j >= 0; j-- {
+			switch {
+			case expect[i] == got[j]:
+				common[i][j] = common[i+1][j+1] + 1
+			case common[i+1][j] >= common[i][j+1]:
+				common[i][j] = common[i+1][j]
+			default:
+				common[i][j] = common[i][j+1]
+			}
+		}
+	}
+
+	result := ""
+	i, j := 0, 0
+	for i < len(expect) || j < len(got) {
+		switch {
+		case i < len(expect) && j < len(got) && expect[i] == got[j]:
+			result += "  " + expect[i] + "\n"
+			i++
+			j++
+		case j == len(got) || (i < len(expect) && common[i+1][j] >= common[i][j+1]):
+			result += "- " + expect[i] + "\n"
+			i++
+		default:
+			result += "+ " + got[j] + "\n"
+			j++
+		}
+	}
+	return result
+}